@@ -0,0 +1,227 @@
+// Command linearize reads dependencies between phases and prints the phases
+// in an order which is safe to run them in, i.e. every phase comes after the
+// phases it depends on.
+//
+// Usage:
+//
+//	linearize [flags] [file]
+//
+// If file is not given (or is "-"), the dependencies are read from stdin. By
+// default they are in the same format tsort uses: pairs of ids separated by
+// whitespace, usually one pair per line, where the first id of each pair must
+// run before the second. A pair of the same id twice adds a phase without any
+// dependencies. With -format=json, they are a JSON object which maps each
+// phase to the phases which must run after it:
+//
+//	{"a": ["b", "c"], "b": ["c"], "d": []}
+//
+// With -format=dot, they are a Graphviz DOT digraph, where a -> b means that
+// a must run before b. See common.ReadTsort, common.ReadJSON and
+// common.ReadDOT for the details of each format.
+//
+// The phases are printed one per line, or with -levels, one level per line,
+// where the phases in each level are separated by spaces and can run at the
+// same time. If there is a cycle, it is printed to stderr instead and the
+// exit status is 1. The default implementation is presort, or kahn with
+// -levels, since presort can't group phases into levels.
+//
+// With -dot, the graph is printed in Graphviz DOT format instead, with each
+// phase labeled with its position in the order, or if there is a cycle, with
+// the cycle drawn in red. It can't be combined with -levels or -json. For
+// example:
+//
+//	linearize -dot deps.txt | dot -Tsvg > deps.svg
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// constructors holds every implementation which can be selected with -impl
+var constructors = map[string]func(opts ...implementations.Option) common.Linearizer{
+	"goraph":      implementations.NewGoraph,
+	"unix":        implementations.NewUnix,
+	"graph":       implementations.NewGraph,
+	"maps":        implementations.NewMaps,
+	"lists":       implementations.NewLists,
+	"presort":     implementations.NewPresort,
+	"kahn":        implementations.NewKahn,
+	"pearcekelly": implementations.NewPearceKelly,
+}
+
+// readers holds the function which reads each format which can be selected with -format
+var readers = map[string]func(r io.Reader, l common.Linearizer) error{
+	"tsort": common.ReadTsort,
+	"json":  common.ReadJSON,
+	"dot":   common.ReadDOT,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with the given arguments and returns the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("linearize", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	impl := flags.String("impl", "", "the implementation to use, one of: "+strings.Join(implNames(), ", ")+
+		" (default presort, or kahn with -levels)")
+	format := flags.String("format", "tsort", "the format of the input, one of: "+strings.Join(formatNames(), ", "))
+	levels := flags.Bool("levels", false, "print the phases in levels which can run at the same time")
+	asJSON := flags.Bool("json", false, "print the results as JSON")
+	strict := flags.Bool("strict", false, "report cycles as soon as a dependency closes one")
+	dot := flags.Bool("dot", false, "print the graph in Graphviz DOT format")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "linearize: expected at most one file")
+		return 2
+	}
+	if *dot && (*levels || *asJSON) {
+		fmt.Fprintln(stderr, "linearize: -dot can't be combined with -levels or -json")
+		return 2
+	}
+	if *impl == "" {
+		*impl = "presort"
+		if *levels {
+			*impl = "kahn"
+		}
+	}
+	newLinearizer, found := constructors[*impl]
+	if !found {
+		fmt.Fprintf(stderr, "linearize: unknown implementation %q, expected one of: %s\n", *impl, strings.Join(implNames(), ", "))
+		return 2
+	}
+	read, found := readers[*format]
+	if !found {
+		fmt.Fprintf(stderr, "linearize: unknown format %q, expected one of: %s\n", *format, strings.Join(formatNames(), ", "))
+		return 2
+	}
+
+	input := stdin
+	if name := flags.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+	opts := []implementations.Option{}
+	if *strict {
+		opts = append(opts, implementations.Strict())
+	}
+	l := newLinearizer(opts...)
+	if *dot {
+		return writeDOT(stdout, stderr, l, read(input, l))
+	}
+	if err := read(input, l); err != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+		return 1
+	}
+	results, err := linearize(l, *levels)
+	if err != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+		return 1
+	}
+	if err := printResults(stdout, results, *asJSON); err != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+func implNames() []string {
+	names := []string{}
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatNames() []string {
+	names := []string{}
+	for name := range readers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// linearize returns either the order of the phases in l,
+// or if levels is true, the levels of l.
+func linearize(l common.Linearizer, levels bool) (interface{}, error) {
+	if !levels {
+		return l.Linearize()
+	}
+	leveler, ok := l.(common.Leveler)
+	if !ok {
+		return nil, fmt.Errorf("%s can't group phases into levels. Try -impl=kahn", l)
+	}
+	return leveler.LinearizeLevels()
+}
+
+func printResults(w io.Writer, results interface{}, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		return encoder.Encode(results)
+	}
+	switch results := results.(type) {
+	case []string:
+		for _, id := range results {
+			if _, err := fmt.Fprintln(w, id); err != nil {
+				return err
+			}
+		}
+	case [][]string:
+		for _, level := range results {
+			if _, err := fmt.Fprintln(w, strings.Join(level, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeDOT writes l as a DOT graph. err is the result of reading the phases
+// and dependencies into l. If it is a cycle error, the cycle is highlighted and
+// the exit status is 1.
+func writeDOT(stdout, stderr io.Writer, l common.Linearizer, err error) int {
+	in, ok := l.(common.Inspector)
+	if !ok {
+		fmt.Fprintf(stderr, "linearize: %s can't be written as DOT. Try -impl=presort\n", l)
+		return 1
+	}
+	opts := common.DOTOptions{}
+	if err == nil {
+		opts.Order, err = l.Linearize()
+	}
+	var cycleErr *common.CycleError
+	if errors.As(err, &cycleErr) {
+		opts.Cycle = cycleErr.Cycle
+	} else if err != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+		return 1
+	}
+	if err := common.WriteDOT(stdout, in, opts); err != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", err.Error())
+		return 1
+	}
+	if cycleErr != nil {
+		fmt.Fprintf(stderr, "linearize: %s\n", cycleErr.Error())
+		return 1
+	}
+	return 0
+}
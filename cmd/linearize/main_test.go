@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRun runs the command without building it, by calling run
+// with the arguments and input of each case
+func TestRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "deps.txt")
+	if err := os.WriteFile(file, []byte("b c\na b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		args     []string
+		input    string
+		status   int
+		expected string
+	}{
+		{
+			args:     []string{},
+			input:    "a b\nb c\nd d\n",
+			expected: "a\nb\nc\nd\n",
+		},
+		{
+			args:     []string{"-impl=kahn", "-levels"},
+			input:    "a b\na c\nb d\nc d\n",
+			expected: "a\nb c\nd\n",
+		},
+		{
+			args:     []string{"-format=json", "-json"},
+			input:    `{"a": ["b", "c"], "b": ["c"], "d": []}`,
+			expected: `["a","b","c","d"]` + "\n",
+		},
+		{
+			args:     []string{"-impl=maps", "-format=json", "-levels", "-json"},
+			input:    `{"a": ["b", "c"], "d": []}`,
+			expected: `[["a","d"],["b","c"]]` + "\n",
+		},
+		{
+			args:     []string{file},
+			expected: "a\nb\nc\n",
+		},
+		{
+			args:     []string{"-impl=pearcekelly"},
+			input:    "a b b c c a",
+			status:   1,
+			expected: "linearize: Could not add dependency c -> a on line 1: Detected cycle: c -> a -> b -> c\n",
+		},
+		{
+			args:     []string{"-strict"},
+			input:    "a b b c c a",
+			status:   1,
+			expected: "linearize: Could not add dependency c -> a on line 1: Detected cycle: c -> a -> b -> c\n",
+		},
+		{
+			// Without -impl, -levels picks an implementation which supports it
+			args:     []string{"-levels"},
+			input:    "a b\na c\nb d\nc d\n",
+			expected: "a\nb c\nd\n",
+		},
+		{
+			args:     []string{"-impl=presort", "-levels"},
+			input:    "a b",
+			status:   1,
+			expected: "linearize: Presort implementation can't group phases into levels. Try -impl=kahn\n",
+		},
+		{
+			args:     []string{"-format=json"},
+			input:    `{"a": "b"}`,
+			status:   1,
+			expected: "linearize: Could not parse JSON on line 1: expected a list of the phases which run after \"a\" but got \"b\"\n",
+		},
+		{
+			args:     []string{"-format=dot"},
+			input:    "digraph {\n\tb -> c\n\ta -> b\n}",
+			expected: "a\nb\nc\n",
+		},
+		{
+			args:     []string{"-format=dot"},
+			input:    "digraph {\n\ta -> \n}",
+			status:   1,
+			expected: "linearize: Could not parse DOT on line 3: expected a node after -> but got \"}\"\n",
+		},
+		{
+			args:     []string{"-format=yaml"},
+			status:   2,
+			expected: "linearize: unknown format \"yaml\", expected one of: dot, json, tsort\n",
+		},
+		{
+			args:     []string{"-dot"},
+			input:    "a b",
+			expected: "digraph dependencies {\n\t\"a\" [label=\"a (1)\"];\n\t\"b\" [label=\"b (2)\"];\n\t\"a\" -> \"b\";\n}\n",
+		},
+		{
+			args:     []string{"-dot", "-levels"},
+			input:    "a b",
+			status:   2,
+			expected: "linearize: -dot can't be combined with -levels or -json\n",
+		},
+		{
+			args:     []string{"-dot", "-json"},
+			input:    "a b",
+			status:   2,
+			expected: "linearize: -dot can't be combined with -levels or -json\n",
+		},
+		{
+			args:     []string{"-impl=none"},
+			status:   2,
+			expected: "linearize: unknown implementation \"none\", expected one of: goraph, graph, kahn, lists, maps, pearcekelly, presort, unix\n",
+		},
+	}
+	for _, c := range cases {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		status := run(c.args, strings.NewReader(c.input), stdout, stderr)
+		if status != c.status {
+			t.Errorf("Expected exit status %d for linearize %v but got %d.\n\tStderr: %s", c.status, c.args, status, stderr)
+		}
+		got := stdout.String()
+		if c.status != 0 {
+			got = stderr.String()
+		}
+		if got != c.expected {
+			t.Errorf("Wrong output for linearize %v with input %q.\n\tExpected: %q\n\tGot: %q", c.args, c.input, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned when the phases cannot be linearized because some
+// of them depend on each other cyclically.
+type CycleError struct {
+	// Cycle holds the ids of the phases on one of the offending cycles, in
+	// order. Each phase must run before the next one, and the last phase
+	// must run before the first, which closes the loop.
+	Cycle []string
+	// Components holds every group of phases which depend on each other
+	// cyclically, i.e. every strongly connected component of the dependency
+	// graph which contains a cycle. Cycle lies within Components[0].
+	Components [][]string
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Cycle) == 0 {
+		return "Detected cycle!"
+	}
+	return fmt.Sprintf("Detected cycle: %s -> %s", strings.Join(e.Cycle, " -> "), e.Cycle[0])
+}
+
+// NewCycleError looks for cycles in the dependency graph made up of the
+// phases in ids and the given edges, where edges[a] contains b iff a must
+// run before b (see MustRunBefore). It returns nil if there are no cycles. The
+// order of ids (and of each edge list) determines which cycle is reported
+// first, so callers should pass them in a stable order.
+func NewCycleError(ids []string, edges map[string][]string) *CycleError {
+	components := cyclicComponents(ids, edges)
+	if len(components) == 0 {
+		return nil
+	}
+	return &CycleError{
+		Cycle:      cycleWithin(components[0], edges),
+		Components: components,
+	}
+}
+
+// cyclicComponents uses Tarjan's algorithm to find the strongly connected
+// components which contain a cycle. Components are ordered by the position
+// of their first phase in ids, and the phases within each component keep
+// their relative order from ids.
+func cyclicComponents(ids []string, edges map[string][]string) [][]string {
+	position := make(map[string]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+	index := make([]int, len(ids))
+	lowLink := make([]int, len(ids))
+	onStack := make([]bool, len(ids))
+	stack := []int{}
+	component := make([]int, len(ids))
+	for i := range component {
+		component[i] = -1
+	}
+	numComponents := 0
+	nextIndex := 1
+
+	var connect func(v int)
+	connect = func(v int) {
+		index[v] = nextIndex
+		lowLink[v] = nextIndex
+		nextIndex++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, id := range edges[ids[v]] {
+			w, found := position[id]
+			if !found {
+				continue
+			}
+			if index[w] == 0 {
+				connect(w)
+				if lowLink[w] < lowLink[v] {
+					lowLink[v] = lowLink[w]
+				}
+			} else if onStack[w] && index[w] < lowLink[v] {
+				lowLink[v] = index[w]
+			}
+		}
+		if lowLink[v] == index[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component[w] = numComponents
+				if w == v {
+					break
+				}
+			}
+			numComponents++
+		}
+	}
+	for v := range ids {
+		if index[v] == 0 {
+			connect(v)
+		}
+	}
+
+	// Group the phases by component, walking ids in order so that both the
+	// components and their members come out in a stable order.
+	sizes := make([]int, numComponents)
+	for _, c := range component {
+		sizes[c]++
+	}
+	grouped := make([]int, numComponents)
+	for i := range grouped {
+		grouped[i] = -1
+	}
+	components := [][]string{}
+	for v, c := range component {
+		if !isCyclic(ids[v], sizes[c], edges) {
+			continue
+		}
+		if grouped[c] == -1 {
+			grouped[c] = len(components)
+			components = append(components, []string{})
+		}
+		components[grouped[c]] = append(components[grouped[c]], ids[v])
+	}
+	return components
+}
+
+// isCyclic returns true iff a strongly connected component of the given size
+// containing id has a cycle. That is always the case for components with more
+// than one phase, but a single phase only forms a cycle if it depends on
+// itself.
+func isCyclic(id string, size int, edges map[string][]string) bool {
+	if size > 1 {
+		return true
+	}
+	for _, other := range edges[id] {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// cycleWithin returns the shortest cycle which starts and ends at the first
+// phase of component, using a breadth-first search which never leaves the
+// component.
+func cycleWithin(component []string, edges map[string][]string) []string {
+	start := component[0]
+	inComponent := make(map[string]struct{}, len(component))
+	for _, id := range component {
+		inComponent[id] = struct{}{}
+	}
+	parents := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if _, found := inComponent[next]; !found {
+				continue
+			}
+			if next == start {
+				// Walk the parents back to start to recover the path.
+				cycle := []string{current}
+				for id := current; id != start; {
+					id = parents[id]
+					cycle = append(cycle, id)
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle
+			}
+			if _, visited := parents[next]; !visited {
+				parents[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	// Unreachable for a strongly connected component with a cycle, but fall
+	// back to reporting the whole component rather than nothing.
+	return component
+}
@@ -0,0 +1,445 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTOptions adds extra information to the graph written by WriteDOT
+type DOTOptions struct {
+	// Order is the linearized order of the phases, e.g. the results of
+	// Linearize. If it is not nil, each phase is labeled with its position
+	// in Order, starting at 1.
+	Order []string
+	// Cycle is a cycle between the phases, e.g. from a *CycleError. If it is
+	// not nil, its dependencies are drawn in red. Dependencies in Cycle which
+	// were never added, like the one which was rejected by AddDependency in
+	// strict mode, are drawn in red with a dashed line.
+	Cycle []string
+}
+
+// WriteDOT writes the phases and dependencies in in to w as a Graphviz DOT
+// graph. Each phase is a node, and each dependency is an edge which points
+// from the phase that must run first to the phase that must run after it.
+func WriteDOT(w io.Writer, in Inspector, opts DOTOptions) error {
+	positions := map[string]int{}
+	for i, id := range opts.Order {
+		positions[id] = i + 1
+	}
+	// cycleEdges holds the edges of opts.Cycle which have not been written yet
+	cycleEdges := map[[2]string]struct{}{}
+	for i, id := range opts.Cycle {
+		cycleEdges[[2]string{id, opts.Cycle[(i+1)%len(opts.Cycle)]}] = struct{}{}
+	}
+
+	lines := []string{"digraph dependencies {"}
+	for _, id := range in.Phases() {
+		if position, found := positions[id]; found {
+			lines = append(lines, fmt.Sprintf("\t%s [label=%s];", quoteDOT(id), quoteDOT(fmt.Sprintf("%s (%d)", id, position))))
+		} else {
+			lines = append(lines, fmt.Sprintf("\t%s;", quoteDOT(id)))
+		}
+	}
+	for _, id := range in.Phases() {
+		for _, dependent := range in.DependentsOf(id) {
+			edge := [2]string{id, dependent}
+			if _, found := cycleEdges[edge]; found {
+				delete(cycleEdges, edge)
+				lines = append(lines, fmt.Sprintf("\t%s -> %s [color=red];", quoteDOT(id), quoteDOT(dependent)))
+			} else {
+				lines = append(lines, fmt.Sprintf("\t%s -> %s;", quoteDOT(id), quoteDOT(dependent)))
+			}
+		}
+	}
+	for i, id := range opts.Cycle {
+		next := opts.Cycle[(i+1)%len(opts.Cycle)]
+		if _, found := cycleEdges[[2]string{id, next}]; found {
+			lines = append(lines, fmt.Sprintf("\t%s -> %s [color=red, style=dashed];", quoteDOT(id), quoteDOT(next)))
+		}
+	}
+	lines = append(lines, "}")
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// quoteDOT returns s as a quoted DOT string
+func quoteDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// ReadDOT reads phases and dependencies from r in Graphviz DOT format and adds
+// them to l. Each node is a phase, and each edge a -> b means that a must run
+// before b. Phases are added in the order they first appear. Attributes are
+// ignored, so the output of WriteDOT can be read back in. Only a single
+// digraph without subgraphs or ports is supported. For example:
+//
+//	digraph {
+//		a -> b -> c;
+//		a -> c;
+//		d;
+//	}
+func ReadDOT(r io.Reader, l Linearizer) error {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p := &dotParser{lexer: &dotLexer{input: string(input), line: 1}}
+	g := newParsedGraph()
+	if err := p.parseGraph(g); err != nil {
+		return err
+	}
+	return g.addTo(l)
+}
+
+// dotTokenKind is the kind of a dotToken
+type dotTokenKind int
+
+const (
+	dotEOF dotTokenKind = iota
+	// dotID is a name, number or quoted string
+	dotID
+	// dotSymbol is punctuation like "{", "->" or ";"
+	dotSymbol
+)
+
+type dotToken struct {
+	kind dotTokenKind
+	text string
+	// quoted is true iff the token was a quoted string,
+	// which is never a keyword
+	quoted bool
+	line   int
+}
+
+func (t dotToken) String() string {
+	if t.kind == dotEOF {
+		return "the end of the input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// isKeyword returns true iff t is the given keyword. Keywords
+// are not case sensitive in DOT, but quoted strings are never
+// keywords.
+func (t dotToken) isKeyword(keyword string) bool {
+	return t.kind == dotID && !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+func (t dotToken) isSymbol(symbol string) bool {
+	return t.kind == dotSymbol && t.text == symbol
+}
+
+// dotLexer splits DOT input into tokens
+type dotLexer struct {
+	input string
+	pos   int
+	line  int
+}
+
+func (lex *dotLexer) errorf(format string, args ...interface{}) error {
+	return &ParseError{Format: "DOT", Line: lex.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next returns the next token, skipping whitespace and comments
+func (lex *dotLexer) next() (dotToken, error) {
+	if err := lex.skipSpace(); err != nil {
+		return dotToken{}, err
+	}
+	if lex.pos >= len(lex.input) {
+		return dotToken{kind: dotEOF, line: lex.line}, nil
+	}
+	start, line := lex.pos, lex.line
+	c := lex.input[lex.pos]
+	switch {
+	case c == '"':
+		text, err := lex.quoted()
+		if err != nil {
+			return dotToken{}, err
+		}
+		return dotToken{kind: dotID, text: text, quoted: true, line: line}, nil
+	case strings.HasPrefix(lex.input[lex.pos:], "->"), strings.HasPrefix(lex.input[lex.pos:], "--"):
+		lex.pos += 2
+	case strings.IndexByte("{}[];,=:", c) != -1:
+		lex.pos++
+	case c == '<':
+		return dotToken{}, lex.errorf("HTML strings are not supported")
+	case isDOTIDChar(c) || c == '-' || c == '.':
+		for lex.pos < len(lex.input) && (isDOTIDChar(lex.input[lex.pos]) || lex.input[lex.pos] == '.') {
+			lex.pos++
+		}
+		if start == lex.pos {
+			// A lone '-' which isn't part of an edge or a number
+			lex.pos++
+			for lex.pos < len(lex.input) && (isDOTIDChar(lex.input[lex.pos]) || lex.input[lex.pos] == '.') {
+				lex.pos++
+			}
+		}
+		return dotToken{kind: dotID, text: lex.input[start:lex.pos], line: line}, nil
+	default:
+		return dotToken{}, lex.errorf("unexpected character %q", c)
+	}
+	return dotToken{kind: dotSymbol, text: lex.input[start:lex.pos], line: line}, nil
+}
+
+// isDOTIDChar returns true iff c can be part of an unquoted id. Any byte
+// outside of ASCII is allowed, so that ids can contain unicode letters.
+func isDOTIDChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c >= 0x80
+}
+
+// skipSpace skips whitespace and comments, counting lines on the way
+func (lex *dotLexer) skipSpace() error {
+	for lex.pos < len(lex.input) {
+		rest := lex.input[lex.pos:]
+		switch {
+		case rest[0] == '\n':
+			lex.line++
+			lex.pos++
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			lex.pos++
+		case strings.HasPrefix(rest, "//") || rest[0] == '#':
+			end := strings.IndexByte(rest, '\n')
+			if end == -1 {
+				end = len(rest)
+			}
+			lex.pos += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end == -1 {
+				return lex.errorf("comment is never closed")
+			}
+			lex.line += strings.Count(rest[:end], "\n")
+			lex.pos += end + 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// quoted reads a quoted string. It undoes the escaping done by WriteDOT,
+// i.e. \" becomes ", \\ becomes \ and \n becomes a new line. A backslash
+// at the end of a line continues the string on the next line.
+func (lex *dotLexer) quoted() (string, error) {
+	line := lex.line
+	lex.pos++
+	var text strings.Builder
+	for lex.pos < len(lex.input) {
+		c := lex.input[lex.pos]
+		lex.pos++
+		switch {
+		case c == '"':
+			return text.String(), nil
+		case c == '\\' && lex.pos < len(lex.input):
+			escaped := lex.input[lex.pos]
+			lex.pos++
+			switch escaped {
+			case '"', '\\':
+				text.WriteByte(escaped)
+			case 'n':
+				text.WriteByte('\n')
+			case '\n':
+				lex.line++
+			default:
+				text.WriteByte(c)
+				text.WriteByte(escaped)
+			}
+		default:
+			if c == '\n' {
+				lex.line++
+			}
+			text.WriteByte(c)
+		}
+	}
+	lex.line = line
+	return "", lex.errorf("string is never closed")
+}
+
+// dotParser reads the statements in a DOT graph
+type dotParser struct {
+	lexer *dotLexer
+	// peeked is the token after the current one, if peek was called
+	peeked *dotToken
+}
+
+func (p *dotParser) next() (dotToken, error) {
+	if p.peeked != nil {
+		t := *p.peeked
+		p.peeked = nil
+		return t, nil
+	}
+	return p.lexer.next()
+}
+
+func (p *dotParser) peek() (dotToken, error) {
+	if p.peeked == nil {
+		t, err := p.lexer.next()
+		if err != nil {
+			return dotToken{}, err
+		}
+		p.peeked = &t
+	}
+	return *p.peeked, nil
+}
+
+func (p *dotParser) errorf(t dotToken, format string, args ...interface{}) error {
+	return &ParseError{Format: "DOT", Line: t.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseGraph parses: [strict] digraph [id] { statements }
+func (p *dotParser) parseGraph(g *parsedGraph) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.isKeyword("strict") {
+		if t, err = p.next(); err != nil {
+			return err
+		}
+	}
+	if t.isKeyword("graph") {
+		return p.errorf(t, "undirected graphs are not supported, use digraph instead")
+	}
+	if !t.isKeyword("digraph") {
+		return p.errorf(t, "expected digraph but got %s", t)
+	}
+	if t, err = p.next(); err != nil {
+		return err
+	}
+	if t.kind == dotID {
+		// Skip the name of the graph
+		if t, err = p.next(); err != nil {
+			return err
+		}
+	}
+	if !t.isSymbol("{") {
+		return p.errorf(t, "expected { but got %s", t)
+	}
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.isSymbol("}"):
+			if t, err = p.next(); err != nil {
+				return err
+			}
+			if t.kind != dotEOF {
+				return p.errorf(t, "expected the end of the input but got %s", t)
+			}
+			return nil
+		case t.isSymbol(";"), t.isSymbol(","):
+			// Statements can be separated by semicolons
+		case t.isKeyword("graph"), t.isKeyword("node"), t.isKeyword("edge"):
+			if err := p.skipAttributes(); err != nil {
+				return err
+			}
+		case t.isKeyword("subgraph"), t.isSymbol("{"):
+			return p.errorf(t, "subgraphs are not supported")
+		case t.kind == dotID:
+			if err := p.parseStatement(g, t); err != nil {
+				return err
+			}
+		default:
+			return p.errorf(t, "expected a statement but got %s", t)
+		}
+	}
+}
+
+// parseStatement parses a node or edge statement which starts with first,
+// or an attribute of the graph like rankdir=LR.
+func (p *dotParser) parseStatement(g *parsedGraph, first dotToken) error {
+	t, err := p.peek()
+	if err != nil {
+		return err
+	}
+	if t.isSymbol("=") {
+		// An attribute of the graph
+		p.next()
+		return p.expectID()
+	}
+	g.addPhase(first.text)
+	before := first
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.isSymbol(":"):
+			return p.errorf(t, "ports are not supported")
+		case t.isSymbol("--"):
+			return p.errorf(t, "undirected edges are not supported, use -> instead")
+		case !t.isSymbol("->"):
+			return p.skipAttributes()
+		}
+		p.next()
+		after, err := p.next()
+		if err != nil {
+			return err
+		}
+		if after.isKeyword("subgraph") || after.isSymbol("{") {
+			return p.errorf(after, "subgraphs are not supported")
+		}
+		if after.kind != dotID {
+			return p.errorf(after, "expected a node after -> but got %s", after)
+		}
+		g.addDependency(before.text, after.text, before.line)
+		before = after
+	}
+}
+
+// skipAttributes skips any number of attribute lists, like [color=red]
+func (p *dotParser) skipAttributes() error {
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return err
+		}
+		if !t.isSymbol("[") {
+			return nil
+		}
+		p.next()
+		for {
+			t, err := p.next()
+			if err != nil {
+				return err
+			}
+			if t.isSymbol("]") {
+				break
+			}
+			if t.kind != dotID {
+				return p.errorf(t, "expected an attribute but got %s", t)
+			}
+			if next, err := p.peek(); err != nil {
+				return err
+			} else if next.isSymbol("=") {
+				p.next()
+				if err := p.expectID(); err != nil {
+					return err
+				}
+			}
+			if next, err := p.peek(); err != nil {
+				return err
+			} else if next.isSymbol(",") || next.isSymbol(";") {
+				p.next()
+			}
+		}
+	}
+}
+
+func (p *dotParser) expectID() error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != dotID {
+		return p.errorf(t, "expected a value but got %s", t)
+	}
+	return nil
+}
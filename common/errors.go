@@ -0,0 +1,25 @@
+package common
+
+import (
+	"errors"
+)
+
+// The errors below are returned (wrapped with the ids involved) by every
+// implementation, so callers can check for them with errors.Is.
+var (
+	// ErrDuplicatePhase is returned by AddPhase when a phase
+	// with the same id has already been added.
+	ErrDuplicatePhase = errors.New("A phase already exists")
+	// ErrSelfDependency is returned when a phase would depend on itself.
+	ErrSelfDependency = errors.New("A phase cannot depend on itself")
+	// ErrUnknownPhase is returned when an id does not belong to any
+	// phase which has been added. It takes priority over
+	// ErrSelfDependency.
+	ErrUnknownPhase = errors.New("Could not find phase")
+	// ErrUnknownDependency is returned when removing a
+	// dependency which was never added.
+	ErrUnknownDependency = errors.New("Could not find dependency")
+	// ErrInvalidOrder is returned by Validate when an order does not
+	// satisfy the phases and dependencies it was checked against.
+	ErrInvalidOrder = errors.New("Invalid order")
+)
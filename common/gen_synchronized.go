@@ -0,0 +1,102 @@
+//go:build ignore
+
+// gen_synchronized writes synchronized_types.go, which declares a type for
+// every combination of the optional interfaces that Synchronized forwards.
+// Run it with go generate after adding an interface to optionalInterfaces.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+// optionalInterfaces are the interfaces which Synchronized forwards, in the
+// order of their bits. Each one needs a synchronized<Name> type in
+// synchronized.go which forwards its methods.
+var optionalInterfaces = []string{
+	"Leveler",
+	"Mutable",
+	"Valuer",
+	"IntoLinearizer",
+	"Handler",
+	"Iterator",
+	"Inspector",
+}
+
+func main() {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by gen_synchronized.go. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package common")
+	fmt.Fprintln(buf)
+
+	fmt.Fprintln(buf, "// synchronizedCombination returns the bits of the optional interfaces which l implements")
+	fmt.Fprintln(buf, "func synchronizedCombination(l Linearizer) int {")
+	fmt.Fprintln(buf, "combination := 0")
+	for i, name := range optionalInterfaces {
+		fmt.Fprintf(buf, "if _, ok := l.(%s); ok {\ncombination |= 1 << %d\n}\n", name, i)
+	}
+	fmt.Fprintln(buf, "return combination")
+	fmt.Fprintln(buf, "}")
+	fmt.Fprintln(buf)
+
+	fmt.Fprintln(buf, "// synchronizedTypes wraps s in the type which implements the optional")
+	fmt.Fprintln(buf, "// interfaces with the given bits, as returned by synchronizedCombination.")
+	fmt.Fprintln(buf, "var synchronizedTypes = [...]func(s *synchronized) Linearizer{")
+	for combination := 0; combination < 1<<len(optionalInterfaces); combination++ {
+		names := namesOf(combination)
+		if len(names) == 0 {
+			fmt.Fprintln(buf, "func(s *synchronized) Linearizer { return s },")
+			continue
+		}
+		conversions := []string{"s"}
+		for _, name := range names {
+			conversions = append(conversions, fmt.Sprintf("(*synchronized%s)(s)", name))
+		}
+		fmt.Fprintf(buf, "func(s *synchronized) Linearizer { return synchronized%d{%s} },\n",
+			combination, strings.Join(conversions, ", "))
+	}
+	fmt.Fprintln(buf, "}")
+
+	for combination := 1; combination < 1<<len(optionalInterfaces); combination++ {
+		names := namesOf(combination)
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "// synchronized%d implements %s\n", combination, describe(names))
+		fmt.Fprintf(buf, "type synchronized%d struct {\n*synchronized\n", combination)
+		for _, name := range names {
+			fmt.Fprintf(buf, "*synchronized%s\n", name)
+		}
+		fmt.Fprintln(buf, "}")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("synchronized_types.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// namesOf returns the names of the interfaces with the given bits
+func namesOf(combination int) []string {
+	names := []string{}
+	for i, name := range optionalInterfaces {
+		if combination&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// describe joins names into a list like "Leveler, Valuer and Handler"
+func describe(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
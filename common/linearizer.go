@@ -1,11 +1,133 @@
 package common
 
+import (
+	"iter"
+)
+
 type Linearizer interface {
 	AddPhase(string) error
+	// Linearize returns the ids of all the phases, ordered so that
+	// every phase comes after the phases it depends on. Given the same
+	// phases and dependencies, added in the same order, it always
+	// returns the same results. Unless an implementation says otherwise,
+	// phases which do not depend on each other keep the order in which
+	// they were added.
 	Linearize() ([]string, error)
-	// AddDependency adds b as a dependency to a.
-	// It reads naturally as "a depends on b"
+	// AddDependency declares that b depends on a, so a will come
+	// before b in the linearized order. Prefer MustRunBefore or
+	// DependsOn, whose names make the direction clear.
 	AddDependency(a, b string) error
 	// Reset clears all previous phases
 	Reset()
 }
+
+// MustRunBefore declares that first must come before second in the
+// linearized order of l. It is the same as l.AddDependency(first, second).
+func MustRunBefore(l Linearizer, first, second string) error {
+	return l.AddDependency(first, second)
+}
+
+// DependsOn declares that dependent depends on prerequisite, so
+// prerequisite must come before dependent in the linearized order of l.
+// It is the same as l.AddDependency(prerequisite, dependent).
+func DependsOn(l Linearizer, dependent, prerequisite string) error {
+	return l.AddDependency(prerequisite, dependent)
+}
+
+// Factory returns a new Linearizer which does not share
+// any state with other Linearizers.
+type Factory func() Linearizer
+
+// Leveler is implemented by Linearizers which can group
+// phases into levels that are safe to run in parallel.
+type Leveler interface {
+	// LinearizeLevels groups the phases into levels, where every
+	// phase only depends on phases in earlier levels. The phases in
+	// a level do not depend on each other, so they can run at the
+	// same time.
+	LinearizeLevels() ([][]string, error)
+}
+
+// Mutable is implemented by Linearizers which can undo
+// calls to AddPhase and AddDependency.
+type Mutable interface {
+	// RemovePhase removes the phase with the given id, along
+	// with every dependency it is a part of.
+	RemovePhase(id string) error
+	// RemoveDependency removes a dependency which was added with
+	// AddDependency(a, b), or equivalently MustRunBefore(a, b).
+	RemoveDependency(a, b string) error
+}
+
+// Valuer is implemented by Linearizers which can hold a value for each
+// phase, e.g. the work it stands for, so there is no need to look it up
+// by id after linearizing.
+type Valuer interface {
+	// AddPhaseWithValue is the same as AddPhase, but also stores value
+	// with the phase. Phases added with AddPhase have a nil value.
+	AddPhaseWithValue(id string, value interface{}) error
+	// Value returns the value of the phase with the given id, or
+	// false if there is no such phase.
+	Value(id string) (interface{}, bool)
+	// LinearizeValues returns the value of each phase, in the
+	// same order Linearize returns their ids.
+	LinearizeValues() ([]interface{}, error)
+}
+
+// IntoLinearizer is implemented by Linearizers which can write
+// their results into a slice provided by the caller.
+type IntoLinearizer interface {
+	// LinearizeInto is the same as Linearize, but writes the ids to
+	// dst[:0] and returns the result, so that dst can be reused.
+	LinearizeInto(dst []string) ([]string, error)
+}
+
+// Iterator is implemented by Linearizers which can return their
+// results one phase at a time, as they are worked out.
+type Iterator interface {
+	// LinearizeIter returns an iterator over the same ids as Linearize, in
+	// the same order, each paired with a nil error. If there is a cycle, the
+	// iteration ends with an empty id paired with the same error Linearize
+	// would return, which may come after the phases that don't depend on the
+	// cycle. Each iteration starts over from the current phases, which must
+	// not be added, removed or given new dependencies until it is done.
+	LinearizeIter() iter.Seq2[string, error]
+}
+
+// PhaseID is a handle for a phase, returned by AddPhaseID. It is only
+// meaningful to the Linearizer which returned it, until Reset is called.
+type PhaseID int
+
+// Handler is implemented by Linearizers which can refer to phases by
+// handle, which is faster than comparing or hashing their ids.
+type Handler interface {
+	// AddPhaseID is the same as AddPhase, but returns
+	// a handle for the new phase.
+	AddPhaseID(id string) (PhaseID, error)
+	// AddDependencyByID is the same as AddDependency, but takes
+	// the handles of the phases instead of their ids.
+	AddDependencyByID(a, b PhaseID) error
+}
+
+// Inspector is implemented by Linearizers which can answer questions
+// about the phases and dependencies they hold. Given the same calls to
+// AddPhase and AddDependency, ids are always returned in the same order.
+type Inspector interface {
+	// HasPhase returns true iff a phase with the given id has been added
+	HasPhase(id string) bool
+	// Phases returns the ids of every phase
+	Phases() []string
+	// DependenciesOf returns the ids of the phases which the phase with
+	// the given id depends on, i.e. the phases which must run before it.
+	// It returns nil if there is no such phase.
+	DependenciesOf(id string) []string
+	// DependentsOf returns the ids of the phases which depend on the phase
+	// with the given id, i.e. the phases which must run after it. It returns
+	// nil if there is no such phase.
+	DependentsOf(id string) []string
+	// NumPhases returns the number of phases
+	NumPhases() int
+	// NumDependencies returns the number of distinct dependencies
+	// between phases
+	NumDependencies() int
+}
@@ -0,0 +1,252 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ParseError is returned by ReadTsort, ReadJSON and ReadDOT
+// when their input is not in the right format.
+type ParseError struct {
+	// Format is the name of the format, e.g. "JSON"
+	Format string
+	// Line is the line of the input the error was found on, starting at 1
+	Line int
+	// Msg describes what was wrong
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Could not parse %s on line %d: %s", e.Format, e.Line, e.Msg)
+}
+
+// parsedGraph holds the phases and dependencies read by one of the readers
+// until they can be added to a Linearizer. That way nothing is added if the
+// input can't be parsed.
+type parsedGraph struct {
+	// phases holds every id in the order it first appeared
+	phases []string
+	seen   map[string]struct{}
+	deps   []parsedDependency
+}
+
+// parsedDependency is a dependency along with the line it was found on
+type parsedDependency struct {
+	Dependency
+	line int
+}
+
+func newParsedGraph() *parsedGraph {
+	return &parsedGraph{
+		seen: map[string]struct{}{},
+	}
+}
+
+func (g *parsedGraph) addPhase(id string) {
+	if _, found := g.seen[id]; !found {
+		g.seen[id] = struct{}{}
+		g.phases = append(g.phases, id)
+	}
+}
+
+func (g *parsedGraph) addDependency(before, after string, line int) {
+	g.addPhase(before)
+	g.addPhase(after)
+	g.deps = append(g.deps, parsedDependency{Dependency{Before: before, After: after}, line})
+}
+
+// addTo adds the phases and then the dependencies in g to l, in the order
+// they appeared in the input. Phases which l already has are left alone. If
+// AddDependency fails, the error is wrapped with the line of the dependency.
+func (g *parsedGraph) addTo(l Linearizer) error {
+	for _, id := range g.phases {
+		if err := l.AddPhase(id); err != nil && !errors.Is(err, ErrDuplicatePhase) {
+			return err
+		}
+	}
+	for _, d := range g.deps {
+		if err := MustRunBefore(l, d.Before, d.After); err != nil {
+			return fmt.Errorf("Could not add dependency %s -> %s on line %d: %w", d.Before, d.After, d.line, err)
+		}
+	}
+	return nil
+}
+
+// ReadTsort reads phases and dependencies from r in the format the tsort
+// command uses, and adds them to l. The input is a list of ids separated by
+// whitespace, where each two ids make up a pair, and the first id of each
+// pair must run before the second. A pair of the same id twice adds a phase
+// without any dependencies. Usually there is one pair per line:
+//
+//	a b
+//	b c
+//	d d
+func ReadTsort(r io.Reader, l Linearizer) error {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	g := newParsedGraph()
+	var first string
+	firstLine := 0
+	line := 1
+	for len(input) > 0 {
+		// Skip to the start of the next token, counting lines on the way
+		i := bytes.IndexFunc(input, func(c rune) bool { return !isTsortDelimiter(c) })
+		if i == -1 {
+			break
+		}
+		line += bytes.Count(input[:i], []byte("\n"))
+		input = input[i:]
+		end := bytes.IndexFunc(input, isTsortDelimiter)
+		if end == -1 {
+			end = len(input)
+		}
+		token := string(input[:end])
+		input = input[end:]
+		if firstLine == 0 {
+			first, firstLine = token, line
+		} else if first == token {
+			g.addPhase(token)
+			firstLine = 0
+		} else {
+			g.addDependency(first, token, firstLine)
+			firstLine = 0
+		}
+	}
+	if firstLine != 0 {
+		return &ParseError{Format: "tsort", Line: firstLine, Msg: fmt.Sprintf("%q is not part of a pair", first)}
+	}
+	return g.addTo(l)
+}
+
+// isTsortDelimiter returns true iff c separates tokens. These are the same
+// characters tsort uses, which is not every kind of whitespace.
+func isTsortDelimiter(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// ReadJSON reads phases and dependencies from r and adds them to l. The
+// input is a JSON object which maps the id of each phase to a list of the
+// phases which must run after it. Phases are added in the order they first
+// appear. For example:
+//
+//	{
+//		"a": ["b", "c"],
+//		"b": ["c"],
+//		"d": []
+//	}
+func ReadJSON(r io.Reader, l Linearizer) error {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p := &jsonParser{input: input, decoder: json.NewDecoder(bytes.NewReader(input))}
+	g := newParsedGraph()
+	if err := p.expectDelim('{', "an object"); err != nil {
+		return err
+	}
+	for p.decoder.More() {
+		before, _, err := p.expectString()
+		if err != nil {
+			return err
+		}
+		g.addPhase(before)
+		if err := p.expectDelim('[', fmt.Sprintf("a list of the phases which run after %q", before)); err != nil {
+			return err
+		}
+		for p.decoder.More() {
+			after, line, err := p.expectString()
+			if err != nil {
+				return err
+			}
+			g.addDependency(before, after, line)
+		}
+		if err := p.expectDelim(']', "the end of the list"); err != nil {
+			return err
+		}
+	}
+	if err := p.expectDelim('}', "the end of the object"); err != nil {
+		return err
+	}
+	if _, err := p.decoder.Token(); err != io.EOF {
+		return p.errorf("expected the end of the input")
+	}
+	return g.addTo(l)
+}
+
+// jsonParser reads one token at a time, so that phases keep the order
+// they appear in, and keeps track of where it is for errors.
+type jsonParser struct {
+	input   []byte
+	decoder *json.Decoder
+}
+
+func (p *jsonParser) expectDelim(delim json.Delim, description string) error {
+	token, err := p.decoder.Token()
+	if err != nil {
+		return p.tokenError(err)
+	}
+	if token != delim {
+		return p.errorf("expected %s but got %s", description, describeJSON(token))
+	}
+	return nil
+}
+
+// expectString returns the next token, which must be
+// a string, along with the line it is on
+func (p *jsonParser) expectString() (string, int, error) {
+	token, err := p.decoder.Token()
+	if err != nil {
+		return "", 0, p.tokenError(err)
+	}
+	s, ok := token.(string)
+	if !ok {
+		return "", 0, p.errorf("expected the id of a phase but got %s", describeJSON(token))
+	}
+	return s, p.line(p.decoder.InputOffset()), nil
+}
+
+func (p *jsonParser) tokenError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return p.errorf("unexpected end of input")
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &ParseError{Format: "JSON", Line: p.line(syntaxErr.Offset), Msg: syntaxErr.Error()}
+	}
+	return p.errorf("%s", err.Error())
+}
+
+// errorf returns a ParseError for the last token which was read
+func (p *jsonParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Format: "JSON", Line: p.line(p.decoder.InputOffset()), Msg: fmt.Sprintf(format, args...)}
+}
+
+// line returns the line of the byte before offset, which
+// is the last byte of the token the decoder just read
+func (p *jsonParser) line(offset int64) int {
+	if offset > 0 {
+		offset--
+	}
+	if offset > int64(len(p.input)) {
+		offset = int64(len(p.input))
+	}
+	return bytes.Count(p.input[:offset], []byte("\n")) + 1
+}
+
+func describeJSON(token json.Token) string {
+	switch token := token.(type) {
+	case json.Delim:
+		return token.String()
+	case string:
+		return fmt.Sprintf("%q", token)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(token)
+	}
+}
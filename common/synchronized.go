@@ -0,0 +1,190 @@
+package common
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// synchronized wraps a Linearizer and serializes
+// calls to it with a mutex.
+type synchronized struct {
+	mut sync.Mutex
+	l   Linearizer
+}
+
+// Each optional interface has a type which forwards its methods to the
+// wrapped Linearizer. Synchronized returns a type from synchronized_types.go,
+// which embeds the ones for the interfaces the wrapped Linearizer implements.
+type (
+	synchronizedLeveler        synchronized
+	synchronizedMutable        synchronized
+	synchronizedValuer         synchronized
+	synchronizedIntoLinearizer synchronized
+	synchronizedHandler        synchronized
+	synchronizedIterator       synchronized
+	synchronizedInspector      synchronized
+)
+
+//go:generate go run gen_synchronized.go
+
+// Synchronized returns a Linearizer which wraps l and only lets one
+// goroutine call its methods at a time, so it is safe to build a single
+// set of phases from multiple goroutines. l should not be used directly
+// after it has been wrapped.
+//
+// The result implements each of Leveler, Mutable, Valuer, IntoLinearizer,
+// Handler, Iterator and Inspector if and only if l does.
+func Synchronized(l Linearizer) Linearizer {
+	return synchronizedTypes[synchronizedCombination(l)](&synchronized{l: l})
+}
+
+func (s *synchronized) AddPhase(id string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.AddPhase(id)
+}
+
+func (s *synchronized) AddDependency(a, b string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.AddDependency(a, b)
+}
+
+func (s *synchronized) Linearize() ([]string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.Linearize()
+}
+
+func (s *synchronized) Reset() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.l.Reset()
+}
+
+func (s *synchronizedLeveler) LinearizeLevels() ([][]string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Leveler).LinearizeLevels()
+}
+
+func (s *synchronizedMutable) RemovePhase(id string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Mutable).RemovePhase(id)
+}
+
+func (s *synchronizedMutable) RemoveDependency(a, b string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Mutable).RemoveDependency(a, b)
+}
+
+func (s *synchronizedValuer) AddPhaseWithValue(id string, value interface{}) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Valuer).AddPhaseWithValue(id, value)
+}
+
+func (s *synchronizedValuer) Value(id string) (interface{}, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Valuer).Value(id)
+}
+
+func (s *synchronizedValuer) LinearizeValues() ([]interface{}, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Valuer).LinearizeValues()
+}
+
+func (s *synchronizedIntoLinearizer) LinearizeInto(dst []string) ([]string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(IntoLinearizer).LinearizeInto(dst)
+}
+
+func (s *synchronizedHandler) AddPhaseID(id string) (PhaseID, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Handler).AddPhaseID(id)
+}
+
+func (s *synchronizedHandler) AddDependencyByID(a, b PhaseID) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Handler).AddDependencyByID(a, b)
+}
+
+// idAndError is one step of the iteration from LinearizeIter
+type idAndError struct {
+	id  string
+	err error
+}
+
+// LinearizeIter holds the lock for the whole iteration of the wrapped
+// Linearizer, and then yields what it got after letting go of the lock.
+// That keeps other goroutines from changing the phases half way through,
+// and lets the loop call other methods, but it means the results are no
+// longer streamed.
+func (s *synchronizedIterator) LinearizeIter() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, step := range s.iterate() {
+			if !yield(step.id, step.err) {
+				return
+			}
+		}
+	}
+}
+
+// iterate collects the whole iteration from s.l while holding the lock
+func (s *synchronizedIterator) iterate() []idAndError {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	steps := []idAndError{}
+	for id, err := range s.l.(Iterator).LinearizeIter() {
+		steps = append(steps, idAndError{id, err})
+	}
+	return steps
+}
+
+func (s *synchronizedInspector) HasPhase(id string) bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).HasPhase(id)
+}
+
+func (s *synchronizedInspector) Phases() []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).Phases()
+}
+
+func (s *synchronizedInspector) DependenciesOf(id string) []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).DependenciesOf(id)
+}
+
+func (s *synchronizedInspector) DependentsOf(id string) []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).DependentsOf(id)
+}
+
+func (s *synchronizedInspector) NumPhases() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).NumPhases()
+}
+
+func (s *synchronizedInspector) NumDependencies() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.l.(Inspector).NumDependencies()
+}
+
+func (s *synchronized) String() string {
+	return fmt.Sprintf("Synchronized %v", s.l)
+}
@@ -0,0 +1,1486 @@
+// Code generated by gen_synchronized.go. DO NOT EDIT.
+
+package common
+
+// synchronizedCombination returns the bits of the optional interfaces which l implements
+func synchronizedCombination(l Linearizer) int {
+	combination := 0
+	if _, ok := l.(Leveler); ok {
+		combination |= 1 << 0
+	}
+	if _, ok := l.(Mutable); ok {
+		combination |= 1 << 1
+	}
+	if _, ok := l.(Valuer); ok {
+		combination |= 1 << 2
+	}
+	if _, ok := l.(IntoLinearizer); ok {
+		combination |= 1 << 3
+	}
+	if _, ok := l.(Handler); ok {
+		combination |= 1 << 4
+	}
+	if _, ok := l.(Iterator); ok {
+		combination |= 1 << 5
+	}
+	if _, ok := l.(Inspector); ok {
+		combination |= 1 << 6
+	}
+	return combination
+}
+
+// synchronizedTypes wraps s in the type which implements the optional
+// interfaces with the given bits, as returned by synchronizedCombination.
+var synchronizedTypes = [...]func(s *synchronized) Linearizer{
+	func(s *synchronized) Linearizer { return s },
+	func(s *synchronized) Linearizer { return synchronized1{s, (*synchronizedLeveler)(s)} },
+	func(s *synchronized) Linearizer { return synchronized2{s, (*synchronizedMutable)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized3{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s)}
+	},
+	func(s *synchronized) Linearizer { return synchronized4{s, (*synchronizedValuer)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized5{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized6{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized7{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s)}
+	},
+	func(s *synchronized) Linearizer { return synchronized8{s, (*synchronizedIntoLinearizer)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized9{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized10{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized11{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized12{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized13{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized14{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized15{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s)}
+	},
+	func(s *synchronized) Linearizer { return synchronized16{s, (*synchronizedHandler)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized17{s, (*synchronizedLeveler)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized18{s, (*synchronizedMutable)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized19{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized20{s, (*synchronizedValuer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized21{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized22{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized23{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized24{s, (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized25{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized26{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized27{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized28{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized29{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized30{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized31{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s)}
+	},
+	func(s *synchronized) Linearizer { return synchronized32{s, (*synchronizedIterator)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized33{s, (*synchronizedLeveler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized34{s, (*synchronizedMutable)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized35{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized36{s, (*synchronizedValuer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized37{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized38{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized39{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized40{s, (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized41{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized42{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized43{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized44{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized45{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized46{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized47{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized48{s, (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized49{s, (*synchronizedLeveler)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized50{s, (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized51{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized52{s, (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized53{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized54{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized55{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized56{s, (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized57{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized58{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized59{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized60{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized61{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized62{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized63{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s)}
+	},
+	func(s *synchronized) Linearizer { return synchronized64{s, (*synchronizedInspector)(s)} },
+	func(s *synchronized) Linearizer {
+		return synchronized65{s, (*synchronizedLeveler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized66{s, (*synchronizedMutable)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized67{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized68{s, (*synchronizedValuer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized69{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized70{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized71{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized72{s, (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized73{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized74{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized75{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized76{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized77{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized78{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized79{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized80{s, (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized81{s, (*synchronizedLeveler)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized82{s, (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized83{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized84{s, (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized85{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized86{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized87{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized88{s, (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized89{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized90{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized91{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized92{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized93{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized94{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized95{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized96{s, (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized97{s, (*synchronizedLeveler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized98{s, (*synchronizedMutable)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized99{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized100{s, (*synchronizedValuer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized101{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized102{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized103{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized104{s, (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized105{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized106{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized107{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized108{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized109{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized110{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized111{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized112{s, (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized113{s, (*synchronizedLeveler)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized114{s, (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized115{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized116{s, (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized117{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized118{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized119{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized120{s, (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized121{s, (*synchronizedLeveler)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized122{s, (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized123{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized124{s, (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized125{s, (*synchronizedLeveler)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized126{s, (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+	func(s *synchronized) Linearizer {
+		return synchronized127{s, (*synchronizedLeveler)(s), (*synchronizedMutable)(s), (*synchronizedValuer)(s), (*synchronizedIntoLinearizer)(s), (*synchronizedHandler)(s), (*synchronizedIterator)(s), (*synchronizedInspector)(s)}
+	},
+}
+
+// synchronized1 implements Leveler
+type synchronized1 struct {
+	*synchronized
+	*synchronizedLeveler
+}
+
+// synchronized2 implements Mutable
+type synchronized2 struct {
+	*synchronized
+	*synchronizedMutable
+}
+
+// synchronized3 implements Leveler and Mutable
+type synchronized3 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+}
+
+// synchronized4 implements Valuer
+type synchronized4 struct {
+	*synchronized
+	*synchronizedValuer
+}
+
+// synchronized5 implements Leveler and Valuer
+type synchronized5 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+}
+
+// synchronized6 implements Mutable and Valuer
+type synchronized6 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+}
+
+// synchronized7 implements Leveler, Mutable and Valuer
+type synchronized7 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+}
+
+// synchronized8 implements IntoLinearizer
+type synchronized8 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+}
+
+// synchronized9 implements Leveler and IntoLinearizer
+type synchronized9 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+}
+
+// synchronized10 implements Mutable and IntoLinearizer
+type synchronized10 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+}
+
+// synchronized11 implements Leveler, Mutable and IntoLinearizer
+type synchronized11 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+}
+
+// synchronized12 implements Valuer and IntoLinearizer
+type synchronized12 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+}
+
+// synchronized13 implements Leveler, Valuer and IntoLinearizer
+type synchronized13 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+}
+
+// synchronized14 implements Mutable, Valuer and IntoLinearizer
+type synchronized14 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+}
+
+// synchronized15 implements Leveler, Mutable, Valuer and IntoLinearizer
+type synchronized15 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+}
+
+// synchronized16 implements Handler
+type synchronized16 struct {
+	*synchronized
+	*synchronizedHandler
+}
+
+// synchronized17 implements Leveler and Handler
+type synchronized17 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedHandler
+}
+
+// synchronized18 implements Mutable and Handler
+type synchronized18 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedHandler
+}
+
+// synchronized19 implements Leveler, Mutable and Handler
+type synchronized19 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedHandler
+}
+
+// synchronized20 implements Valuer and Handler
+type synchronized20 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedHandler
+}
+
+// synchronized21 implements Leveler, Valuer and Handler
+type synchronized21 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedHandler
+}
+
+// synchronized22 implements Mutable, Valuer and Handler
+type synchronized22 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+}
+
+// synchronized23 implements Leveler, Mutable, Valuer and Handler
+type synchronized23 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+}
+
+// synchronized24 implements IntoLinearizer and Handler
+type synchronized24 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized25 implements Leveler, IntoLinearizer and Handler
+type synchronized25 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized26 implements Mutable, IntoLinearizer and Handler
+type synchronized26 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized27 implements Leveler, Mutable, IntoLinearizer and Handler
+type synchronized27 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized28 implements Valuer, IntoLinearizer and Handler
+type synchronized28 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized29 implements Leveler, Valuer, IntoLinearizer and Handler
+type synchronized29 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized30 implements Mutable, Valuer, IntoLinearizer and Handler
+type synchronized30 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized31 implements Leveler, Mutable, Valuer, IntoLinearizer and Handler
+type synchronized31 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+}
+
+// synchronized32 implements Iterator
+type synchronized32 struct {
+	*synchronized
+	*synchronizedIterator
+}
+
+// synchronized33 implements Leveler and Iterator
+type synchronized33 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIterator
+}
+
+// synchronized34 implements Mutable and Iterator
+type synchronized34 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIterator
+}
+
+// synchronized35 implements Leveler, Mutable and Iterator
+type synchronized35 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIterator
+}
+
+// synchronized36 implements Valuer and Iterator
+type synchronized36 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIterator
+}
+
+// synchronized37 implements Leveler, Valuer and Iterator
+type synchronized37 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIterator
+}
+
+// synchronized38 implements Mutable, Valuer and Iterator
+type synchronized38 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIterator
+}
+
+// synchronized39 implements Leveler, Mutable, Valuer and Iterator
+type synchronized39 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIterator
+}
+
+// synchronized40 implements IntoLinearizer and Iterator
+type synchronized40 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized41 implements Leveler, IntoLinearizer and Iterator
+type synchronized41 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized42 implements Mutable, IntoLinearizer and Iterator
+type synchronized42 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized43 implements Leveler, Mutable, IntoLinearizer and Iterator
+type synchronized43 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized44 implements Valuer, IntoLinearizer and Iterator
+type synchronized44 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized45 implements Leveler, Valuer, IntoLinearizer and Iterator
+type synchronized45 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized46 implements Mutable, Valuer, IntoLinearizer and Iterator
+type synchronized46 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized47 implements Leveler, Mutable, Valuer, IntoLinearizer and Iterator
+type synchronized47 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+}
+
+// synchronized48 implements Handler and Iterator
+type synchronized48 struct {
+	*synchronized
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized49 implements Leveler, Handler and Iterator
+type synchronized49 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized50 implements Mutable, Handler and Iterator
+type synchronized50 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized51 implements Leveler, Mutable, Handler and Iterator
+type synchronized51 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized52 implements Valuer, Handler and Iterator
+type synchronized52 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized53 implements Leveler, Valuer, Handler and Iterator
+type synchronized53 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized54 implements Mutable, Valuer, Handler and Iterator
+type synchronized54 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized55 implements Leveler, Mutable, Valuer, Handler and Iterator
+type synchronized55 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized56 implements IntoLinearizer, Handler and Iterator
+type synchronized56 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized57 implements Leveler, IntoLinearizer, Handler and Iterator
+type synchronized57 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized58 implements Mutable, IntoLinearizer, Handler and Iterator
+type synchronized58 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized59 implements Leveler, Mutable, IntoLinearizer, Handler and Iterator
+type synchronized59 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized60 implements Valuer, IntoLinearizer, Handler and Iterator
+type synchronized60 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized61 implements Leveler, Valuer, IntoLinearizer, Handler and Iterator
+type synchronized61 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized62 implements Mutable, Valuer, IntoLinearizer, Handler and Iterator
+type synchronized62 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized63 implements Leveler, Mutable, Valuer, IntoLinearizer, Handler and Iterator
+type synchronized63 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+}
+
+// synchronized64 implements Inspector
+type synchronized64 struct {
+	*synchronized
+	*synchronizedInspector
+}
+
+// synchronized65 implements Leveler and Inspector
+type synchronized65 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedInspector
+}
+
+// synchronized66 implements Mutable and Inspector
+type synchronized66 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedInspector
+}
+
+// synchronized67 implements Leveler, Mutable and Inspector
+type synchronized67 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedInspector
+}
+
+// synchronized68 implements Valuer and Inspector
+type synchronized68 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedInspector
+}
+
+// synchronized69 implements Leveler, Valuer and Inspector
+type synchronized69 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedInspector
+}
+
+// synchronized70 implements Mutable, Valuer and Inspector
+type synchronized70 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedInspector
+}
+
+// synchronized71 implements Leveler, Mutable, Valuer and Inspector
+type synchronized71 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedInspector
+}
+
+// synchronized72 implements IntoLinearizer and Inspector
+type synchronized72 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized73 implements Leveler, IntoLinearizer and Inspector
+type synchronized73 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized74 implements Mutable, IntoLinearizer and Inspector
+type synchronized74 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized75 implements Leveler, Mutable, IntoLinearizer and Inspector
+type synchronized75 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized76 implements Valuer, IntoLinearizer and Inspector
+type synchronized76 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized77 implements Leveler, Valuer, IntoLinearizer and Inspector
+type synchronized77 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized78 implements Mutable, Valuer, IntoLinearizer and Inspector
+type synchronized78 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized79 implements Leveler, Mutable, Valuer, IntoLinearizer and Inspector
+type synchronized79 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedInspector
+}
+
+// synchronized80 implements Handler and Inspector
+type synchronized80 struct {
+	*synchronized
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized81 implements Leveler, Handler and Inspector
+type synchronized81 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized82 implements Mutable, Handler and Inspector
+type synchronized82 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized83 implements Leveler, Mutable, Handler and Inspector
+type synchronized83 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized84 implements Valuer, Handler and Inspector
+type synchronized84 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized85 implements Leveler, Valuer, Handler and Inspector
+type synchronized85 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized86 implements Mutable, Valuer, Handler and Inspector
+type synchronized86 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized87 implements Leveler, Mutable, Valuer, Handler and Inspector
+type synchronized87 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized88 implements IntoLinearizer, Handler and Inspector
+type synchronized88 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized89 implements Leveler, IntoLinearizer, Handler and Inspector
+type synchronized89 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized90 implements Mutable, IntoLinearizer, Handler and Inspector
+type synchronized90 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized91 implements Leveler, Mutable, IntoLinearizer, Handler and Inspector
+type synchronized91 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized92 implements Valuer, IntoLinearizer, Handler and Inspector
+type synchronized92 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized93 implements Leveler, Valuer, IntoLinearizer, Handler and Inspector
+type synchronized93 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized94 implements Mutable, Valuer, IntoLinearizer, Handler and Inspector
+type synchronized94 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized95 implements Leveler, Mutable, Valuer, IntoLinearizer, Handler and Inspector
+type synchronized95 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedInspector
+}
+
+// synchronized96 implements Iterator and Inspector
+type synchronized96 struct {
+	*synchronized
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized97 implements Leveler, Iterator and Inspector
+type synchronized97 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized98 implements Mutable, Iterator and Inspector
+type synchronized98 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized99 implements Leveler, Mutable, Iterator and Inspector
+type synchronized99 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized100 implements Valuer, Iterator and Inspector
+type synchronized100 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized101 implements Leveler, Valuer, Iterator and Inspector
+type synchronized101 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized102 implements Mutable, Valuer, Iterator and Inspector
+type synchronized102 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized103 implements Leveler, Mutable, Valuer, Iterator and Inspector
+type synchronized103 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized104 implements IntoLinearizer, Iterator and Inspector
+type synchronized104 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized105 implements Leveler, IntoLinearizer, Iterator and Inspector
+type synchronized105 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized106 implements Mutable, IntoLinearizer, Iterator and Inspector
+type synchronized106 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized107 implements Leveler, Mutable, IntoLinearizer, Iterator and Inspector
+type synchronized107 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized108 implements Valuer, IntoLinearizer, Iterator and Inspector
+type synchronized108 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized109 implements Leveler, Valuer, IntoLinearizer, Iterator and Inspector
+type synchronized109 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized110 implements Mutable, Valuer, IntoLinearizer, Iterator and Inspector
+type synchronized110 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized111 implements Leveler, Mutable, Valuer, IntoLinearizer, Iterator and Inspector
+type synchronized111 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized112 implements Handler, Iterator and Inspector
+type synchronized112 struct {
+	*synchronized
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized113 implements Leveler, Handler, Iterator and Inspector
+type synchronized113 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized114 implements Mutable, Handler, Iterator and Inspector
+type synchronized114 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized115 implements Leveler, Mutable, Handler, Iterator and Inspector
+type synchronized115 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized116 implements Valuer, Handler, Iterator and Inspector
+type synchronized116 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized117 implements Leveler, Valuer, Handler, Iterator and Inspector
+type synchronized117 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized118 implements Mutable, Valuer, Handler, Iterator and Inspector
+type synchronized118 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized119 implements Leveler, Mutable, Valuer, Handler, Iterator and Inspector
+type synchronized119 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized120 implements IntoLinearizer, Handler, Iterator and Inspector
+type synchronized120 struct {
+	*synchronized
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized121 implements Leveler, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized121 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized122 implements Mutable, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized122 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized123 implements Leveler, Mutable, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized123 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized124 implements Valuer, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized124 struct {
+	*synchronized
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized125 implements Leveler, Valuer, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized125 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized126 implements Mutable, Valuer, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized126 struct {
+	*synchronized
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
+
+// synchronized127 implements Leveler, Mutable, Valuer, IntoLinearizer, Handler, Iterator and Inspector
+type synchronized127 struct {
+	*synchronized
+	*synchronizedLeveler
+	*synchronizedMutable
+	*synchronizedValuer
+	*synchronizedIntoLinearizer
+	*synchronizedHandler
+	*synchronizedIterator
+	*synchronizedInspector
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"sort"
+)
+
+// TieBreak decides the order of phases which are ready at the same
+// time, i.e. which do not depend on each other. It reports whether the
+// phase with id a should come before the phase with id b. Phases for
+// which it returns false both ways keep the order they were added.
+type TieBreak func(a, b string) bool
+
+// InsertionOrder keeps phases in the order they were added.
+// It is nil, which is the default for every TieBreak option.
+var InsertionOrder TieBreak
+
+// Lexicographic orders phases by id.
+var Lexicographic TieBreak = func(a, b string) bool {
+	return a < b
+}
+
+// ByPriority returns a TieBreak which puts phases with a higher
+// priority first. Phases which are not in priorities have a
+// priority of 0.
+func ByPriority(priorities map[string]int) TieBreak {
+	return func(a, b string) bool {
+		return priorities[a] > priorities[b]
+	}
+}
+
+// Sort sorts ids in place with t. ids should already be in the order the
+// phases were added, so that ties keep that order. If t is nil, ids are
+// left as they are.
+func (t TieBreak) Sort(ids []string) {
+	if t == nil {
+		return
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return t(ids[i], ids[j])
+	})
+}
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"fmt"
+)
+
+// Dependency is a single constraint on the order of two phases:
+// Before must run before After.
+type Dependency struct {
+	Before string
+	After  string
+}
+
+// Validate checks that order is a valid linearization of phases with the
+// given dependencies, i.e. that every phase appears in order exactly once
+// and that every dependency is respected. Any valid order is accepted, not
+// just the one a particular implementation would return. If order is not
+// valid, the error wraps ErrInvalidOrder and describes the first problem
+// found. If a dependency refers to a phase which is not in phases, the
+// error wraps ErrUnknownPhase instead, and if a phase depends on itself,
+// which no order can satisfy, it wraps ErrSelfDependency.
+func Validate(phases []string, deps []Dependency, order []string) error {
+	known := make(map[string]struct{}, len(phases))
+	for _, id := range phases {
+		known[id] = struct{}{}
+	}
+	for _, d := range deps {
+		for _, id := range []string{d.Before, d.After} {
+			if _, found := known[id]; !found {
+				return fmt.Errorf("%w with id = %s", ErrUnknownPhase, id)
+			}
+		}
+		if d.Before == d.After {
+			return fmt.Errorf("%w (id = %s)", ErrSelfDependency, d.Before)
+		}
+	}
+	positions := make(map[string]int, len(order))
+	for i, id := range order {
+		if _, found := known[id]; !found {
+			return fmt.Errorf("%w: unexpected phase with id = %s at position %d", ErrInvalidOrder, id, i)
+		}
+		if first, found := positions[id]; found {
+			return fmt.Errorf("%w: phase with id = %s appears at both position %d and %d", ErrInvalidOrder, id, first, i)
+		}
+		positions[id] = i
+	}
+	for _, id := range phases {
+		if _, found := positions[id]; !found {
+			return fmt.Errorf("%w: missing phase with id = %s", ErrInvalidOrder, id)
+		}
+	}
+	for _, d := range deps {
+		if positions[d.Before] > positions[d.After] {
+			return fmt.Errorf("%w: %s must run before %s but came after it (position %d > %d)",
+				ErrInvalidOrder, d.Before, d.After, positions[d.Before], positions[d.After])
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package implementations
 
 import (
 	"errors"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
 	"github.com/gyuho/goraph/algorithm/tsdag"
 	"github.com/gyuho/goraph/graph/gs"
 	"strings"
@@ -9,36 +11,102 @@ import (
 
 type goraphType struct {
 	graph *gs.Graph
+	// ids and edges mirror the vertices and edges of graph so that
+	// we can report cycles. edges[a] contains b iff a comes before b.
+	ids   []string
+	edges map[string][]string
+	options
 }
 
-var Goraph = &goraphType{
-	graph: gs.NewGraph(),
+// Deprecated: Goraph is shared by everyone who uses it, so it is not safe
+// to build more than one set of phases at a time. Use NewGoraph instead.
+var Goraph = newGoraph()
+
+// NewGoraph returns a new, independent Goraph implementation. The order
+// of phases which do not depend on each other is decided by the
+// topological sort in github.com/gyuho/goraph. It ignores the WithTieBreak
+// option.
+func NewGoraph(opts ...Option) common.Linearizer {
+	return newGoraph(opts...)
+}
+
+func newGoraph(opts ...Option) *goraphType {
+	return &goraphType{
+		graph:   gs.NewGraph(),
+		edges:   map[string][]string{},
+		options: newOptions(opts),
+	}
 }
 
 func (g *goraphType) AddPhase(id string) error {
+	if g.graph.FindVertexByID(id) != nil {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
 	vertex := gs.NewVertex(id)
 	g.graph.AddVertex(vertex)
+	g.ids = append(g.ids, id)
 	return nil
 }
 
 func (g *goraphType) AddDependency(a, b string) error {
 	va := g.graph.FindVertexByID(a)
+	if va == nil {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
 	vb := g.graph.FindVertexByID(b)
+	if vb == nil {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if g.strict {
+		if err := checkNewDependency(a, b, g.dependentsOf); err != nil {
+			return err
+		}
+	}
 	g.graph.Connect(va, vb, 0)
+	g.edges[a] = append(g.edges[a], b)
 	return nil
 }
 
+func (g *goraphType) dependentsOf(id string) []string {
+	return g.edges[id]
+}
+
 func (g *goraphType) Linearize() ([]string, error) {
+	if len(g.ids) == 0 {
+		// Splitting the empty output would give us one empty id
+		return []string{}, nil
+	}
 	sorted, ok := tsdag.TSDAG(g.graph)
 	if !ok {
+		if err := common.NewCycleError(g.ids, g.edges); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("Could not linearize dependencies. Was there a cycle?")
 	}
 	ids := strings.Split(sorted, " → ")
 	return ids, nil
 }
 
+// LinearizeInto copies the results of Linearize into dst,
+// so it still allocates as much as Linearize does.
+func (g *goraphType) LinearizeInto(dst []string) ([]string, error) {
+	ids, err := g.Linearize()
+	if err != nil {
+		return nil, err
+	}
+	return append(dst[:0], ids...), nil
+}
+
+// Reset has to start over with a new graph, since goraph has no way
+// to remove vertices, so building the phases again allocates as much
+// as it did the first time.
 func (g *goraphType) Reset() {
 	g.graph = gs.NewGraph()
+	g.ids = g.ids[:0]
+	clear(g.edges)
 }
 
 func (g *goraphType) String() string {
@@ -3,6 +3,7 @@ package implementations
 import (
 	"errors"
 	"fmt"
+	"github.com/albrow/dependency-linearization/common"
 	"github.com/twmb/algoimpl/go/graph"
 	"reflect"
 )
@@ -10,58 +11,231 @@ import (
 type graphType struct {
 	graph  *graph.Graph
 	phases map[string]graph.Node
+	// ids and edges mirror the nodes and edges of graph so that
+	// we can report cycles and rebuild graph after removing things.
+	// edges[a] contains b iff a comes before b.
+	ids   []string
+	edges map[string][]string
+	options
 }
 
-var Graph = &graphType{
-	graph:  graph.New(graph.Directed),
-	phases: map[string]graph.Node{},
+// Deprecated: Graph is shared by everyone who uses it, so it is not safe
+// to build more than one set of phases at a time. Use NewGraph instead.
+var Graph = newGraph()
+
+// NewGraph returns a new, independent Graph implementation. The order
+// of phases which do not depend on each other is decided by the strongly
+// connected components algorithm in github.com/twmb/algoimpl. It ignores
+// the WithTieBreak option.
+func NewGraph(opts ...Option) common.Linearizer {
+	return newGraph(opts...)
+}
+
+func newGraph(opts ...Option) *graphType {
+	return &graphType{
+		graph:   graph.New(graph.Directed),
+		phases:  map[string]graph.Node{},
+		edges:   map[string][]string{},
+		options: newOptions(opts),
+	}
+}
+
+// graphPhase is stored in the Value of each node
+type graphPhase struct {
+	id    string
+	value interface{}
 }
 
 func (g *graphType) AddPhase(id string) error {
+	return g.AddPhaseWithValue(id, nil)
+}
+
+func (g *graphType) AddPhaseWithValue(id string, value interface{}) error {
+	if _, found := g.phases[id]; found {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
+	g.ids = append(g.ids, id)
 	node := g.graph.MakeNode()
-	*node.Value = id
+	*node.Value = graphPhase{id: id, value: value}
 	g.phases[id] = node
 	return nil
 }
 
+func (g *graphType) Value(id string) (interface{}, bool) {
+	node, found := g.phases[id]
+	if !found {
+		return nil, false
+	}
+	phase, err := nodePhase(node)
+	if err != nil {
+		return nil, false
+	}
+	return phase.value, true
+}
+
 func (g *graphType) AddDependency(a, b string) error {
 	va, found := g.phases[a]
 	if !found {
-		return fmt.Errorf("Could not find phase with id = %s", a)
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
 	}
 	vb, found := g.phases[b]
 	if !found {
-		return fmt.Errorf("Could not find phase with id = %s", b)
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if g.strict {
+		if err := checkNewDependency(a, b, g.dependentsOf); err != nil {
+			return err
+		}
 	}
 	g.graph.MakeEdge(va, vb)
+	g.edges[a] = append(g.edges[a], b)
+	return nil
+}
+
+func (g *graphType) dependentsOf(id string) []string {
+	return g.edges[id]
+}
+
+func (g *graphType) RemovePhase(id string) error {
+	if _, found := g.phases[id]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, id)
+	}
+	g.ids = without(g.ids, id)
+	delete(g.edges, id)
+	for a, bs := range g.edges {
+		g.edges[a] = without(bs, id)
+	}
+	g.rebuild()
+	return nil
+}
+
+func (g *graphType) RemoveDependency(a, b string) error {
+	if _, found := g.phases[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	if _, found := g.phases[b]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	remaining := without(g.edges[a], b)
+	if len(remaining) == len(g.edges[a]) {
+		return fmt.Errorf("%w %s -> %s", common.ErrUnknownDependency, a, b)
+	}
+	g.edges[a] = remaining
+	g.rebuild()
 	return nil
 }
 
+// rebuild replaces graph with a new one made from ids and edges.
+// The graph package doesn't let us remove nodes or edges, so this
+// is the only way to undo AddPhase and AddDependency.
+func (g *graphType) rebuild() {
+	oldPhases := g.phases
+	g.graph = graph.New(graph.Directed)
+	g.phases = map[string]graph.Node{}
+	for _, id := range g.ids {
+		node := g.graph.MakeNode()
+		// Keep the value of the phase from the old node
+		*node.Value = *oldPhases[id].Value
+		g.phases[id] = node
+	}
+	for _, a := range g.ids {
+		for _, b := range g.edges[a] {
+			g.graph.MakeEdge(g.phases[a], g.phases[b])
+		}
+	}
+}
+
 func (g *graphType) Linearize() ([]string, error) {
+	return g.LinearizeInto(make([]string, 0, len(g.ids)))
+}
+
+// LinearizeInto writes the ids straight into dst, but the graph package
+// still allocates the components it sorts the phases into.
+func (g *graphType) LinearizeInto(dst []string) ([]string, error) {
+	components, err := g.components()
+	if err != nil {
+		return nil, err
+	}
+	dst = dst[:0]
+	for _, list := range components {
+		phase, err := nodePhase(list[0])
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, phase.id)
+	}
+	return dst, nil
+}
+
+func (g *graphType) LinearizeValues() ([]interface{}, error) {
+	components, err := g.components()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(components))
+	for _, list := range components {
+		phase, err := nodePhase(list[0])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, phase.value)
+	}
+	return values, nil
+}
+
+// components returns the strongly connected components of the graph in
+// order. Unless there is a cycle, each component holds a single phase.
+func (g *graphType) components() ([][]graph.Node, error) {
 	components := g.graph.StronglyConnectedComponents()
 	if len(components) != len(g.phases) {
-		return nil, errors.New("cycle detected!")
+		return nil, g.cycleError(components)
 	}
+	return components, nil
+}
+
+// cycleError returns a CycleError describing the cycles in the given
+// strongly connected components.
+func (g *graphType) cycleError(components [][]graph.Node) error {
 	ids := []string{}
 	for _, list := range components {
-		node := list[0]
-		id, ok := (*node.Value).(string)
-		if !ok {
-			msg := fmt.Sprintf("Could not convert value: %v to string!", node.Value)
-			if node.Value != nil {
-				typ := reflect.TypeOf(*node.Value)
-				msg += fmt.Sprintf(" Had type: %s", typ.String())
+		for _, node := range list {
+			phase, err := nodePhase(node)
+			if err != nil {
+				return err
 			}
-			return nil, errors.New(msg)
+			ids = append(ids, phase.id)
 		}
-		ids = append(ids, id)
 	}
-	return ids, nil
+	if err := common.NewCycleError(ids, g.edges); err != nil {
+		return err
+	}
+	return errors.New("Could not linearize phases. Was there a cycle?")
 }
 
+func nodePhase(node graph.Node) (graphPhase, error) {
+	phase, ok := (*node.Value).(graphPhase)
+	if !ok {
+		msg := fmt.Sprintf("Could not convert value: %v to graphPhase!", node.Value)
+		if node.Value != nil {
+			typ := reflect.TypeOf(*node.Value)
+			msg += fmt.Sprintf(" Had type: %s", typ.String())
+		}
+		return graphPhase{}, errors.New(msg)
+	}
+	return phase, nil
+}
+
+// Reset has to start over with a new graph, since the graph package has
+// no way to remove nodes, so building the phases again allocates as much
+// as it did the first time.
 func (g *graphType) Reset() {
 	g.graph = graph.New(graph.Directed)
-	g.phases = map[string]graph.Node{}
+	clear(g.phases)
+	g.ids = g.ids[:0]
+	clear(g.edges)
 }
 
 func (g *graphType) String() string {
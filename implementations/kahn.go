@@ -0,0 +1,33 @@
+package implementations
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/internal/core"
+)
+
+// kahnType is core.Kahn with string ids. The algorithm lives in
+// internal/core so that typed.NewKahn shares it.
+type kahnType struct {
+	*core.Kahn[string, interface{}]
+}
+
+// NewKahn returns a new, independent implementation of Kahn's algorithm.
+// It keeps track of the number of dependencies each phase has, and whenever
+// a phase is taken off the queue of ready phases, it decrements the count for
+// each phase that depends on it. Phases whose count reaches zero are added to
+// the queue. Linearize takes O(V+E) time for V phases and E dependencies.
+// Whenever several phases are ready at the same time, they are kept in the
+// order they were added, unless the WithTieBreak option says otherwise.
+func NewKahn(opts ...Option) common.Linearizer {
+	return newKahn(opts...)
+}
+
+func newKahn(opts ...Option) *kahnType {
+	return &kahnType{
+		Kahn: core.NewKahn[string, interface{}](newOptions(opts).coreOptions()),
+	}
+}
+
+func (k *kahnType) String() string {
+	return "Kahn implementation"
+}
@@ -3,15 +3,44 @@ package implementations
 import (
 	"container/list"
 	"fmt"
+	"github.com/albrow/dependency-linearization/common"
 )
 
 type listsType struct {
 	// A linked list of linked lists representing dependencies
 	phases *list.List
+	// elements maps the id of each phase to its element in phases
+	elements map[string]*list.Element
+	// spare holds the lists of dependencies from before the
+	// last Reset, which AddPhase reuses
+	spare []*list.List
+	// done and starts are kept between calls to levelsInto
+	done   map[string]struct{}
+	starts []int
+	// dependents is only kept up to date in strict mode
+	dependents dependentsIndex
+	options
 }
 
-var Lists = &listsType{
-	phases: list.New(),
+// Deprecated: Lists is shared by everyone who uses it, so it is not safe
+// to build more than one set of phases at a time. Use NewLists instead.
+var Lists = newLists()
+
+// NewLists returns a new, independent Lists implementation. Whenever
+// several phases are ready at the same time, they are kept in the
+// order they were added, unless the WithTieBreak option says otherwise.
+func NewLists(opts ...Option) common.Linearizer {
+	return newLists(opts...)
+}
+
+func newLists(opts ...Option) *listsType {
+	return &listsType{
+		phases:     list.New(),
+		elements:   map[string]*list.Element{},
+		done:       map[string]struct{}{},
+		dependents: dependentsIndex{},
+		options:    newOptions(opts),
+	}
 }
 
 type phase struct {
@@ -20,69 +49,308 @@ type phase struct {
 }
 
 func (l *listsType) AddPhase(id string) error {
-	l.phases.PushFront(phase{
-		deps: list.New(),
+	if l.HasPhase(id) {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
+	var deps *list.List
+	if n := len(l.spare); n > 0 {
+		deps = l.spare[n-1]
+		l.spare = l.spare[:n-1]
+	} else {
+		deps = list.New()
+	}
+	l.elements[id] = l.phases.PushBack(phase{
+		deps: deps,
 		id:   id,
 	})
 	return nil
 }
 
 func (c *listsType) AddDependency(a, b string) error {
+	if _, found := c.elements[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	pb, found := c.find(b)
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if c.strict {
+		if err := checkNewDependency(a, b, c.dependents.of); err != nil {
+			return err
+		}
+		c.dependents.add(a, b)
+	}
+	pb.deps.PushBack(a)
+	return nil
+}
+
+func (c *listsType) RemovePhase(id string) error {
+	toRemove, found := c.elements[id]
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, id)
+	}
 	for e := c.phases.Front(); e != nil; e = e.Next() {
 		p, ok := e.Value.(phase)
 		if !ok {
 			return fmt.Errorf("Could not convert %v of type %T to phase!", e.Value, e.Value)
 		}
-		if p.id == b {
-			p.deps.PushFront(a)
-			return nil
+		if _, err := removeDep(p.deps, id); err != nil {
+			return err
 		}
 	}
-	return fmt.Errorf("Could not find phase with id = %s", b)
+	delete(c.elements, id)
+	if c.strict {
+		c.dependents.removePhase(id)
+	}
+	c.spare = append(c.spare, mustPhase(toRemove).deps.Init())
+	c.phases.Remove(toRemove)
+	return nil
+}
+
+func (c *listsType) RemoveDependency(a, b string) error {
+	if _, found := c.elements[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	pb, found := c.find(b)
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	removed, err := removeDep(pb.deps, a)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("%w %s -> %s", common.ErrUnknownDependency, a, b)
+	}
+	if c.strict {
+		c.dependents.remove(a, b)
+	}
+	return nil
+}
+
+// removeDep removes every occurrence of id from deps.
+// It returns true iff anything was removed.
+func removeDep(deps *list.List, id string) (bool, error) {
+	removed := false
+	for dep := deps.Front(); dep != nil; {
+		// Remove clears the links of dep, so we need
+		// to get the next element first
+		next := dep.Next()
+		depId, ok := dep.Value.(string)
+		if !ok {
+			return false, fmt.Errorf("Could not convert %v of type %T to string!", dep.Value, dep.Value)
+		}
+		if depId == id {
+			deps.Remove(dep)
+			removed = true
+		}
+		dep = next
+	}
+	return removed, nil
 }
 
 func (c *listsType) Linearize() ([]string, error) {
-	results := []string{}
-	for c.phases.Len() > 0 {
-		deleted := []string{}
+	return c.LinearizeInto(make([]string, 0, c.phases.Len()))
+}
+
+// LinearizeInto doesn't allocate anything once dst and the memory it
+// works in are big enough, unless the WithTieBreak option is used.
+func (c *listsType) LinearizeInto(dst []string) ([]string, error) {
+	dst, _, err := c.levelsInto(dst)
+	return dst, err
+}
+
+func (c *listsType) LinearizeLevels() ([][]string, error) {
+	order, starts, err := c.levelsInto(nil)
+	if err != nil {
+		return nil, err
+	}
+	return splitLevels(order, starts), nil
+}
+
+// levelsInto writes the phases into dst[:0] one level at a time, where
+// level l is dst[starts[l]:starts[l+1]]. starts is reused by the next call.
+func (c *listsType) levelsInto(dst []string) ([]string, []int, error) {
+	dst = dst[:0]
+	c.starts = append(c.starts[:0], 0)
+	// done holds the phases which have already been put in a level. We
+	// keep track of them here instead of removing them from c.phases, so
+	// that linearizing does not destroy the dependency graph.
+	done := c.done
+	clear(done)
+	for len(done) < c.phases.Len() {
+		start := len(dst)
 		for e := c.phases.Front(); e != nil; e = e.Next() {
 			p, ok := e.Value.(phase)
 			if !ok {
-				return nil, fmt.Errorf("Could not convert %v of type %T to phase!", e.Value, e.Value)
+				return nil, nil, fmt.Errorf("Could not convert %v of type %T to phase!", e.Value, e.Value)
 			}
-			if p.deps.Len() == 0 {
-				results = append(results, p.id)
-				c.phases.Remove(e)
-				deleted = append(deleted, p.id)
+			if _, found := done[p.id]; found {
+				continue
 			}
+			ready, err := p.ready(done)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ready {
+				dst = append(dst, p.id)
+			}
+		}
+		level := dst[start:]
+		if len(level) == 0 {
+			return nil, nil, c.cycleError(done)
+		}
+		c.tieBreak.Sort(level)
+		for _, id := range level {
+			done[id] = struct{}{}
+		}
+		c.starts = append(c.starts, len(dst))
+	}
+	return dst, c.starts, nil
+}
+
+// ready returns true iff every dependency of p is in done
+func (p phase) ready(done map[string]struct{}) (bool, error) {
+	for dep := p.deps.Front(); dep != nil; dep = dep.Next() {
+		depId, ok := dep.Value.(string)
+		if !ok {
+			return false, fmt.Errorf("Could not convert %v of type %T to string!", dep.Value, dep.Value)
 		}
-		if len(deleted) == 0 {
-			return nil, fmt.Errorf("Detected cycle!")
-		} else {
-			for _, phaseToDelete := range deleted {
-				for e := c.phases.Front(); e != nil; e = e.Next() {
-					p, ok := e.Value.(phase)
-					if !ok {
-						return nil, fmt.Errorf("Could not convert %v of type %T to phase!", e.Value, e.Value)
-					}
-					for dep := p.deps.Front(); dep != nil; dep = dep.Next() {
-						depId, ok := dep.Value.(string)
-						if !ok {
-							return nil, fmt.Errorf("Could not convert %v of type %T to string!", dep.Value, dep.Value)
-						}
-						if depId == phaseToDelete {
-							p.deps.Remove(dep)
-						}
-					}
-				}
+		if _, found := done[depId]; !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// cycleError returns a CycleError describing the phases which are left over
+// (i.e. not in done) after LinearizeLevels could not make any more progress.
+func (c *listsType) cycleError(done map[string]struct{}) error {
+	ids := []string{}
+	edges := map[string][]string{}
+	for e := c.phases.Front(); e != nil; e = e.Next() {
+		p, ok := e.Value.(phase)
+		if !ok {
+			return fmt.Errorf("Could not convert %v of type %T to phase!", e.Value, e.Value)
+		}
+		if _, found := done[p.id]; found {
+			continue
+		}
+		ids = append(ids, p.id)
+		for dep := p.deps.Front(); dep != nil; dep = dep.Next() {
+			depId, ok := dep.Value.(string)
+			if !ok {
+				return fmt.Errorf("Could not convert %v of type %T to string!", dep.Value, dep.Value)
+			}
+			edges[depId] = append(edges[depId], p.id)
+		}
+	}
+	if err := common.NewCycleError(ids, edges); err != nil {
+		return err
+	}
+	return fmt.Errorf("Could not linearize phases %v", ids)
+}
+
+func (c *listsType) HasPhase(id string) bool {
+	_, found := c.find(id)
+	return found
+}
+
+func (c *listsType) Phases() []string {
+	ids := []string{}
+	for e := c.phases.Front(); e != nil; e = e.Next() {
+		ids = append(ids, mustPhase(e).id)
+	}
+	return ids
+}
+
+func (c *listsType) DependenciesOf(id string) []string {
+	p, found := c.find(id)
+	if !found {
+		return nil
+	}
+	return unique(p.depIds())
+}
+
+func (c *listsType) DependentsOf(id string) []string {
+	if _, found := c.find(id); !found {
+		return nil
+	}
+	results := []string{}
+	for e := c.phases.Front(); e != nil; e = e.Next() {
+		p := mustPhase(e)
+		for _, depId := range p.depIds() {
+			if depId == id {
+				results = append(results, p.id)
+				break
 			}
 		}
 	}
-	return results, nil
+	return results
+}
+
+func (c *listsType) NumPhases() int {
+	return c.phases.Len()
+}
+
+func (c *listsType) NumDependencies() int {
+	count := 0
+	for e := c.phases.Front(); e != nil; e = e.Next() {
+		count += len(unique(mustPhase(e).depIds()))
+	}
+	return count
+}
+
+// find returns the phase with the given id
+func (c *listsType) find(id string) (phase, bool) {
+	e, found := c.elements[id]
+	if !found {
+		return phase{}, false
+	}
+	return mustPhase(e), true
+}
+
+// mustPhase returns the phase held by e. It panics if e holds
+// anything else, which would mean there is a bug in listsType.
+func mustPhase(e *list.Element) phase {
+	p, ok := e.Value.(phase)
+	if !ok {
+		msg := fmt.Sprintf("Could not convert %v of type %T to phase!", e.Value, e.Value)
+		panic(msg)
+	}
+	return p
+}
+
+// depIds returns the ids of the phases which p depends on. It
+// panics if p.deps holds anything other than strings, which
+// would mean there is a bug in listsType.
+func (p phase) depIds() []string {
+	ids := []string{}
+	for dep := p.deps.Front(); dep != nil; dep = dep.Next() {
+		depId, ok := dep.Value.(string)
+		if !ok {
+			msg := fmt.Sprintf("Could not convert %v of type %T to string!", dep.Value, dep.Value)
+			panic(msg)
+		}
+		ids = append(ids, depId)
+	}
+	return ids
 }
 
+// Reset keeps the lists of dependencies so that AddPhase can reuse them,
+// but container/list has no way to reuse its elements, so building the
+// phases again still allocates one for each phase and each dependency,
+// along with the value stored in each element.
 func (c *listsType) Reset() {
+	for e := c.phases.Back(); e != nil; e = e.Prev() {
+		c.spare = append(c.spare, mustPhase(e).deps.Init())
+	}
 	c.phases.Init()
+	clear(c.elements)
+	clear(c.dependents)
 }
 
 func (c *listsType) String() string {
@@ -2,54 +2,295 @@ package implementations
 
 import (
 	"fmt"
+	"github.com/albrow/dependency-linearization/common"
 )
 
 type mapsType struct {
 	// A map of phases to the phases they depend on
 	phases map[string]map[string]struct{}
+	// The ids of all phases in the order they were added.
+	// We range over this instead of phases so that the
+	// results don't depend on map order.
+	order []string
+	// spare holds the sets of dependencies from before the
+	// last Reset, which AddPhase reuses
+	spare []map[string]struct{}
+	// done and starts are kept between calls to levelsInto
+	done   map[string]struct{}
+	starts []int
+	// dependents is only kept up to date in strict mode
+	dependents dependentsIndex
+	options
 }
 
-var Maps = &mapsType{
-	phases: map[string]map[string]struct{}{},
+// Deprecated: Maps is shared by everyone who uses it, so it is not safe
+// to build more than one set of phases at a time. Use NewMaps instead.
+var Maps = newMaps()
+
+// NewMaps returns a new, independent Maps implementation. Whenever
+// several phases are ready at the same time, they are kept in the
+// order they were added, unless the WithTieBreak option says otherwise.
+func NewMaps(opts ...Option) common.Linearizer {
+	return newMaps(opts...)
+}
+
+func newMaps(opts ...Option) *mapsType {
+	return &mapsType{
+		phases:     map[string]map[string]struct{}{},
+		done:       map[string]struct{}{},
+		dependents: dependentsIndex{},
+		options:    newOptions(opts),
+	}
 }
 
 func (c *mapsType) AddPhase(id string) error {
-	c.phases[id] = map[string]struct{}{}
+	if _, found := c.phases[id]; found {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
+	c.order = append(c.order, id)
+	if n := len(c.spare); n > 0 {
+		c.phases[id] = c.spare[n-1]
+		c.spare = c.spare[:n-1]
+	} else {
+		c.phases[id] = map[string]struct{}{}
+	}
 	return nil
 }
 
 func (c *mapsType) AddDependency(a, b string) error {
+	if _, found := c.phases[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
 	if _, found := c.phases[b]; !found {
-		return fmt.Errorf("Could not find phase with id = %s", b)
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if c.strict {
+		if err := checkNewDependency(a, b, c.dependents.of); err != nil {
+			return err
+		}
+		if _, found := c.phases[b][a]; !found {
+			c.dependents.add(a, b)
+		}
 	}
 	c.phases[b][a] = struct{}{}
 	return nil
 }
 
+func (c *mapsType) RemovePhase(id string) error {
+	if _, found := c.phases[id]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, id)
+	}
+	c.spare = append(c.spare, c.phases[id])
+	clear(c.phases[id])
+	delete(c.phases, id)
+	c.order = without(c.order, id)
+	for _, deps := range c.phases {
+		delete(deps, id)
+	}
+	if c.strict {
+		c.dependents.removePhase(id)
+	}
+	return nil
+}
+
+func (c *mapsType) RemoveDependency(a, b string) error {
+	if _, found := c.phases[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	if _, found := c.phases[b]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if _, found := c.phases[b][a]; !found {
+		return fmt.Errorf("%w %s -> %s", common.ErrUnknownDependency, a, b)
+	}
+	delete(c.phases[b], a)
+	if c.strict {
+		c.dependents.remove(a, b)
+	}
+	return nil
+}
+
 func (c *mapsType) Linearize() ([]string, error) {
-	results := []string{}
-	for len(c.phases) > 0 {
-		deleted := []string{}
-		for phase, deps := range c.phases {
+	return c.LinearizeInto(make([]string, 0, len(c.order)))
+}
+
+// LinearizeInto doesn't allocate anything once dst and the memory it
+// works in are big enough, unless the WithTieBreak option is used.
+func (c *mapsType) LinearizeInto(dst []string) ([]string, error) {
+	dst, _, err := c.levelsInto(dst)
+	return dst, err
+}
+
+func (c *mapsType) LinearizeLevels() ([][]string, error) {
+	order, starts, err := c.levelsInto(nil)
+	if err != nil {
+		return nil, err
+	}
+	return splitLevels(order, starts), nil
+}
+
+// levelsInto writes the phases into dst[:0] one level at a time, where
+// level l is dst[starts[l]:starts[l+1]]. starts is reused by the next call.
+func (c *mapsType) levelsInto(dst []string) ([]string, []int, error) {
+	dst = dst[:0]
+	c.starts = append(c.starts[:0], 0)
+	// done holds the phases which have already been put in a level. We
+	// keep track of them here instead of deleting them from c.phases, so
+	// that linearizing does not destroy the dependency graph.
+	done := c.done
+	clear(done)
+	for len(done) < len(c.phases) {
+		start := len(dst)
+		for _, phase := range c.order {
+			if _, found := done[phase]; found {
+				continue
+			}
 			// Find the phases which have no dependencies left
-			// Add them to results and remove them from deps map
-			if len(deps) == 0 {
-				results = append(results, phase)
-				delete(c.phases, phase)
-				deleted = append(deleted, phase)
+			// and add them to the current level
+			if allDone(c.phases[phase], done) {
+				dst = append(dst, phase)
 			}
 		}
-		if len(deleted) == 0 {
-			return nil, fmt.Errorf("Detected cycle!")
-		} else {
-			for _, phaseToDelete := range deleted {
-				for _, deps := range c.phases {
-					delete(deps, phaseToDelete)
-				}
-			}
+		level := dst[start:]
+		if len(level) == 0 {
+			return nil, nil, c.cycleError(done)
+		}
+		c.tieBreak.Sort(level)
+		for _, phase := range level {
+			done[phase] = struct{}{}
+		}
+		c.starts = append(c.starts, len(dst))
+	}
+	return dst, c.starts, nil
+}
+
+// allDone returns true iff every phase in deps is in done
+func allDone(deps map[string]struct{}, done map[string]struct{}) bool {
+	for dep := range deps {
+		if _, found := done[dep]; !found {
+			return false
 		}
 	}
-	return results, nil
+	return true
+}
+
+// cycleError returns a CycleError describing the phases which are left over
+// (i.e. not in done) after LinearizeLevels could not make any more progress.
+func (c *mapsType) cycleError(done map[string]struct{}) error {
+	ids := []string{}
+	edges := map[string][]string{}
+	for _, phase := range c.order {
+		if _, found := done[phase]; found {
+			continue
+		}
+		ids = append(ids, phase)
+		for dep := range c.phases[phase] {
+			edges[dep] = append(edges[dep], phase)
+		}
+	}
+	if err := common.NewCycleError(ids, edges); err != nil {
+		return err
+	}
+	return fmt.Errorf("Could not linearize phases %v", ids)
+}
+
+// splitLevels returns the levels in order, where level l is
+// order[starts[l]:starts[l+1]]. The levels share the memory of order.
+func splitLevels(order []string, starts []int) [][]string {
+	levels := make([][]string, len(starts)-1)
+	for l := range levels {
+		levels[l] = order[starts[l]:starts[l+1]:starts[l+1]]
+	}
+	return levels
+}
+
+func (c *mapsType) HasPhase(id string) bool {
+	_, found := c.phases[id]
+	return found
+}
+
+func (c *mapsType) Phases() []string {
+	return append([]string{}, c.order...)
+}
+
+func (c *mapsType) DependenciesOf(id string) []string {
+	deps, found := c.phases[id]
+	if !found {
+		return nil
+	}
+	// deps is a set, so walk c.order to keep the results in a stable order
+	results := []string{}
+	for _, other := range c.order {
+		if _, found := deps[other]; found {
+			results = append(results, other)
+		}
+	}
+	return results
+}
+
+func (c *mapsType) DependentsOf(id string) []string {
+	if _, found := c.phases[id]; !found {
+		return nil
+	}
+	results := []string{}
+	for _, other := range c.order {
+		if _, found := c.phases[other][id]; found {
+			results = append(results, other)
+		}
+	}
+	return results
+}
+
+func (c *mapsType) NumPhases() int {
+	return len(c.phases)
+}
+
+func (c *mapsType) NumDependencies() int {
+	count := 0
+	for _, deps := range c.phases {
+		count += len(deps)
+	}
+	return count
+}
+
+// unique returns ids without any repeats, keeping the
+// first occurrence of each id.
+func unique(ids []string) []string {
+	seen := map[string]struct{}{}
+	results := []string{}
+	for _, id := range ids {
+		if _, found := seen[id]; !found {
+			seen[id] = struct{}{}
+			results = append(results, id)
+		}
+	}
+	return results
+}
+
+// without returns ids with every occurrence of id removed.
+// It reuses the memory of ids.
+func without(ids []string, id string) []string {
+	results := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			results = append(results, other)
+		}
+	}
+	return results
+}
+
+// appendEmpty appends an empty slice to s. If s has room, it reuses the
+// slice which was there before, which keeps its memory for later appends.
+func appendEmpty(s [][]int) [][]int {
+	if len(s) < cap(s) {
+		s = s[:len(s)+1]
+		s[len(s)-1] = s[len(s)-1][:0]
+		return s
+	}
+	return append(s, nil)
 }
 
 func mapKeys(m map[string]struct{}) []string {
@@ -60,8 +301,20 @@ func mapKeys(m map[string]struct{}) []string {
 	return keys
 }
 
+// Reset keeps the memory it has allocated so far, including the set of
+// dependencies of each phase, so that building the same phases again
+// doesn't allocate.
 func (c *mapsType) Reset() {
-	c.phases = map[string]map[string]struct{}{}
+	// Going backwards means AddPhase gives each
+	// set to the same phase as before
+	for i := len(c.order) - 1; i >= 0; i-- {
+		deps := c.phases[c.order[i]]
+		clear(deps)
+		c.spare = append(c.spare, deps)
+	}
+	clear(c.phases)
+	clear(c.dependents)
+	c.order = c.order[:0]
 }
 
 func (c *mapsType) String() string {
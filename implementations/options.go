@@ -0,0 +1,78 @@
+package implementations
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/internal/core"
+)
+
+// Option changes the behavior of an implementation. Pass options
+// to the constructors, e.g. NewMaps(WithTieBreak(common.Lexicographic)).
+type Option func(*options)
+
+type options struct {
+	tieBreak  common.TieBreak
+	strict    bool
+	inProcess bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithTieBreak sets the order of phases which are ready at the same
+// time. It is only supported by Maps, Lists and Kahn, which all default to
+// common.InsertionOrder. The other implementations ignore it.
+//
+// The phases are sorted one level at a time, where a level holds the phases
+// whose dependencies all come in earlier levels, just like LinearizeLevels.
+// The whole level comes before any phase which depends on it, so a phase
+// from a later level never moves ahead of it, even if tieBreak says so. For
+// example, with common.Lexicographic and a dependency from b to a, the order
+// of a, b and c is b, c, a and not b, a, c.
+func WithTieBreak(tieBreak common.TieBreak) Option {
+	return func(o *options) {
+		o.tieBreak = tieBreak
+	}
+}
+
+// Strict makes AddDependency check whether the new dependency would close a
+// loop, and if so, return a *common.CycleError without adding it. Cycle[0]
+// and Cycle[1] are the phases of the new dependency, and the rest of Cycle is
+// the existing path which leads from Cycle[1] back to Cycle[0]. Each check has
+// to search the phases which depend on Cycle[1], so AddDependency is slower
+// in strict mode. It is supported by every implementation except PearceKelly,
+// which always behaves this way.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// InProcess makes Unix sort the phases with the tsort package instead of
+// running the tsort command. The results are the same, but it is much
+// faster, it works on machines without tsort, and ids may contain
+// whitespace. It is only supported by Unix.
+func InProcess() Option {
+	return func(o *options) {
+		o.inProcess = true
+	}
+}
+
+// coreOptions converts o into the options of the
+// implementations which are built on internal/core
+func (o options) coreOptions() core.Options[string] {
+	coreOpts := core.Options[string]{
+		Strict: o.strict,
+		CycleError: func(cycle []string, components [][]string) error {
+			return &common.CycleError{Cycle: cycle, Components: components}
+		},
+	}
+	if o.tieBreak != nil {
+		coreOpts.TieBreak = o.tieBreak
+	}
+	return coreOpts
+}
@@ -0,0 +1,283 @@
+package implementations
+
+import (
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"sort"
+)
+
+type pearceKellyType struct {
+	// ids holds the id of every phase in the order they were added.
+	// Everything else refers to phases by their index in ids.
+	ids []string
+	// indexes maps each phase to its index in ids
+	indexes map[string]int
+	// dependents holds the indexes of the phases which depend on each phase
+	dependents [][]int
+	// prerequisites holds the indexes of the phases each phase depends on
+	prerequisites [][]int
+	// order holds the index of the phase at each position of the current
+	// topological order, and positions is the inverse of order
+	order     []int
+	positions []int
+	// visited is used by AddDependency to mark the phases it has searched.
+	// It is always cleared again before AddDependency returns.
+	visited []bool
+	options
+}
+
+// NewPearceKelly returns a new, independent implementation of the dynamic
+// topological sort algorithm by Pearce and Kelly. It keeps the phases in a
+// valid order at all times. When a new dependency does not fit the current
+// order, it only reorders the phases whose positions are between the two
+// phases involved. Because of that, AddDependency returns a *common.CycleError
+// as soon as a dependency would close a loop (without adding the dependency),
+// and Linearize takes O(V) time for V phases. Phases are kept in the order
+// they were added, except where a dependency requires moving them. It takes
+// options like the other constructors, but always behaves as if it were
+// given Strict, and ignores WithTieBreak.
+func NewPearceKelly(opts ...Option) common.Linearizer {
+	return newPearceKelly(opts...)
+}
+
+func newPearceKelly(opts ...Option) *pearceKellyType {
+	return &pearceKellyType{
+		indexes: map[string]int{},
+		options: newOptions(opts),
+	}
+}
+
+func (pk *pearceKellyType) AddPhase(id string) error {
+	if _, found := pk.indexes[id]; found {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
+	index := len(pk.ids)
+	pk.indexes[id] = index
+	pk.ids = append(pk.ids, id)
+	pk.dependents = appendEmpty(pk.dependents)
+	pk.prerequisites = appendEmpty(pk.prerequisites)
+	// New phases don't depend on anything yet, so they can go last
+	pk.positions = append(pk.positions, len(pk.order))
+	pk.order = append(pk.order, index)
+	pk.visited = append(pk.visited, false)
+	return nil
+}
+
+func (pk *pearceKellyType) AddDependency(a, b string) error {
+	x, found := pk.indexes[a]
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	y, found := pk.indexes[b]
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if pk.positions[x] > pk.positions[y] {
+		// The dependency does not fit the current order. Only the phases
+		// between y and x in the order could be affected.
+		lowerBound, upperBound := pk.positions[y], pk.positions[x]
+		forward, cycle := pk.searchForward(y, x, upperBound)
+		if cycle != nil {
+			pk.clearVisited(forward)
+			return pk.cycleError(x, y, cycle)
+		}
+		backward := pk.searchBackward(x, lowerBound)
+		pk.clearVisited(forward)
+		pk.clearVisited(backward)
+		pk.reorder(backward, forward)
+	}
+	pk.dependents[x] = append(pk.dependents[x], y)
+	pk.prerequisites[y] = append(pk.prerequisites[y], x)
+	return nil
+}
+
+// searchForward returns every phase which depends (directly or indirectly)
+// on start and comes before upperBound in the current order. If target also
+// depends on start, adding a dependency from target to start would close a
+// loop. In that case, it also returns the path from start to target, not
+// including target.
+func (pk *pearceKellyType) searchForward(start, target, upperBound int) ([]int, []int) {
+	found := []int{start}
+	pk.visited[start] = true
+	parents := map[int]int{}
+	stack := []int{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, dependent := range pk.dependents[current] {
+			if dependent == target {
+				path := []int{current}
+				for path[0] != start {
+					path = append([]int{parents[path[0]]}, path...)
+				}
+				return found, path
+			}
+			if !pk.visited[dependent] && pk.positions[dependent] < upperBound {
+				pk.visited[dependent] = true
+				parents[dependent] = current
+				found = append(found, dependent)
+				stack = append(stack, dependent)
+			}
+		}
+	}
+	return found, nil
+}
+
+// searchBackward returns every phase which start depends on (directly or
+// indirectly) and comes after lowerBound in the current order.
+func (pk *pearceKellyType) searchBackward(start, lowerBound int) []int {
+	found := []int{start}
+	pk.visited[start] = true
+	stack := []int{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, prerequisite := range pk.prerequisites[current] {
+			if !pk.visited[prerequisite] && pk.positions[prerequisite] > lowerBound {
+				pk.visited[prerequisite] = true
+				found = append(found, prerequisite)
+				stack = append(stack, prerequisite)
+			}
+		}
+	}
+	return found
+}
+
+func (pk *pearceKellyType) clearVisited(indexes []int) {
+	for _, i := range indexes {
+		pk.visited[i] = false
+	}
+}
+
+// reorder moves the phases in backward ahead of the phases in forward,
+// reusing the positions they already occupy between them. The phases in
+// each group keep their order relative to each other.
+func (pk *pearceKellyType) reorder(backward, forward []int) {
+	pk.sortByPosition(backward)
+	pk.sortByPosition(forward)
+	phases := append(backward, forward...)
+	positions := make([]int, len(phases))
+	for i, phase := range phases {
+		positions[i] = pk.positions[phase]
+	}
+	sort.Ints(positions)
+	for i, phase := range phases {
+		pk.positions[phase] = positions[i]
+		pk.order[positions[i]] = phase
+	}
+}
+
+func (pk *pearceKellyType) sortByPosition(indexes []int) {
+	sort.Slice(indexes, func(i, j int) bool {
+		return pk.positions[indexes[i]] < pk.positions[indexes[j]]
+	})
+}
+
+// cycleError returns a CycleError for the loop which adding a dependency from
+// x to y would close, where path leads from y back to one of x's prerequisites.
+func (pk *pearceKellyType) cycleError(x, y int, path []int) error {
+	cycle := []string{pk.ids[x]}
+	for _, i := range path {
+		cycle = append(cycle, pk.ids[i])
+	}
+	// The components are worked out as if the dependency had been added
+	edges := pk.edges()
+	edges[pk.ids[x]] = append(edges[pk.ids[x]], pk.ids[y])
+	components := [][]string{cycle}
+	if err := common.NewCycleError(pk.ids, edges); err != nil {
+		components = err.Components
+	}
+	return &common.CycleError{
+		Cycle:      cycle,
+		Components: components,
+	}
+}
+
+// edges returns the dependencies by id, in the format NewCycleError expects
+func (pk *pearceKellyType) edges() map[string][]string {
+	edges := map[string][]string{}
+	for i, id := range pk.ids {
+		edges[id] = pk.idsOf(pk.dependents[i])
+	}
+	return edges
+}
+
+func (pk *pearceKellyType) idsOf(indexes []int) []string {
+	ids := make([]string, len(indexes))
+	for i, index := range indexes {
+		ids[i] = pk.ids[index]
+	}
+	return ids
+}
+
+// Linearize returns the order which AddDependency has been maintaining.
+// Since a dependency which would close a loop is never added, it never
+// returns an error.
+func (pk *pearceKellyType) Linearize() ([]string, error) {
+	return pk.LinearizeInto(make([]string, 0, len(pk.order)))
+}
+
+// LinearizeInto doesn't allocate anything once dst is big enough
+func (pk *pearceKellyType) LinearizeInto(dst []string) ([]string, error) {
+	dst = dst[:0]
+	for _, i := range pk.order {
+		dst = append(dst, pk.ids[i])
+	}
+	return dst, nil
+}
+
+func (pk *pearceKellyType) HasPhase(id string) bool {
+	_, found := pk.indexes[id]
+	return found
+}
+
+func (pk *pearceKellyType) Phases() []string {
+	return append([]string{}, pk.ids...)
+}
+
+func (pk *pearceKellyType) DependenciesOf(id string) []string {
+	index, found := pk.indexes[id]
+	if !found {
+		return nil
+	}
+	return unique(pk.idsOf(pk.prerequisites[index]))
+}
+
+func (pk *pearceKellyType) DependentsOf(id string) []string {
+	index, found := pk.indexes[id]
+	if !found {
+		return nil
+	}
+	return unique(pk.idsOf(pk.dependents[index]))
+}
+
+func (pk *pearceKellyType) NumPhases() int {
+	return len(pk.ids)
+}
+
+func (pk *pearceKellyType) NumDependencies() int {
+	count := 0
+	for _, dependents := range pk.dependents {
+		count += len(unique(pk.idsOf(dependents)))
+	}
+	return count
+}
+
+// Reset keeps the memory it has allocated so far, so that
+// building the same number of phases again doesn't allocate.
+func (pk *pearceKellyType) Reset() {
+	pk.ids = pk.ids[:0]
+	clear(pk.indexes)
+	pk.dependents = pk.dependents[:0]
+	pk.prerequisites = pk.prerequisites[:0]
+	pk.order = pk.order[:0]
+	pk.positions = pk.positions[:0]
+	pk.visited = pk.visited[:0]
+}
+
+func (pk *pearceKellyType) String() string {
+	return "PearceKelly implementation"
+}
@@ -0,0 +1,50 @@
+package implementations
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/internal/core"
+)
+
+// checkNewDependency returns a CycleError if adding a dependency from a to b
+// would close a loop, i.e. if a already depends (directly or indirectly) on
+// b. dependentsOf returns the ids of the phases which depend directly on the
+// phase with the given id. See core.FindNewCycle.
+func checkNewDependency(a, b string, dependentsOf func(id string) []string) error {
+	cycle, component := core.FindNewCycle(a, b, dependentsOf)
+	if cycle == nil {
+		return nil
+	}
+	return &common.CycleError{
+		Cycle:      cycle,
+		Components: [][]string{component},
+	}
+}
+
+// dependentsIndex maps the id of each phase to the ids of the phases which
+// depend on it, in the order the dependencies were added. Implementations
+// which otherwise only know what each phase depends on keep one up to date
+// in strict mode, so that checkNewDependency can look up the dependents of
+// each phase it visits instead of searching every phase for them.
+type dependentsIndex map[string][]string
+
+// add records that b depends on a
+func (d dependentsIndex) add(a, b string) {
+	d[a] = append(d[a], b)
+}
+
+// remove forgets every dependency of b on a
+func (d dependentsIndex) remove(a, b string) {
+	d[a] = without(d[a], b)
+}
+
+// removePhase forgets every dependency which id is a part of
+func (d dependentsIndex) removePhase(id string) {
+	delete(d, id)
+	for other, dependents := range d {
+		d[other] = without(dependents, id)
+	}
+}
+
+func (d dependentsIndex) of(id string) []string {
+	return d[id]
+}
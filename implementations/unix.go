@@ -3,81 +3,149 @@ package implementations
 import (
 	"bytes"
 	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/tsort"
 	"os/exec"
 	"strings"
 )
 
 type unixType struct {
 	phases map[string]struct{}
-	deps   []dep
+	// ids holds the id of every phase in the order they were added
+	ids  []string
+	deps []dep
+	// dependents is kept up to date so that strict mode
+	// doesn't have to rebuild it from deps each time
+	dependents dependentsIndex
+	options
 }
 
+// dep means first must come before second
 type dep struct {
-	depender  string
-	dependsOn string
+	first  string
+	second string
 }
 
-var Unix = &unixType{
-	phases: map[string]struct{}{},
+// Deprecated: Unix is shared by everyone who uses it, so it is not safe
+// to build more than one set of phases at a time. Use NewUnix instead.
+var Unix = newUnix()
+
+// NewUnix returns a new, independent Unix implementation. The order
+// of phases which do not depend on each other is decided by tsort. By
+// default it runs the tsort command, which means ids can't be empty or
+// contain whitespace. With the InProcess option it uses the tsort package
+// instead, which gives the same results without those restrictions. It
+// ignores the WithTieBreak option.
+func NewUnix(opts ...Option) common.Linearizer {
+	return newUnix(opts...)
+}
+
+func newUnix(opts ...Option) *unixType {
+	return &unixType{
+		phases:     map[string]struct{}{},
+		dependents: dependentsIndex{},
+		options:    newOptions(opts),
+	}
 }
 
 func (u *unixType) AddPhase(id string) error {
+	if _, found := u.phases[id]; found {
+		return fmt.Errorf("%w with id = %s", common.ErrDuplicatePhase, id)
+	}
+	if !u.inProcess {
+		if err := tsort.CheckToken(id); err != nil {
+			return fmt.Errorf("%w can't be passed to the tsort command. Use the InProcess option instead", err)
+		}
+	}
+	u.ids = append(u.ids, id)
 	u.phases[id] = struct{}{}
 	return nil
 }
 
 func (u *unixType) AddDependency(a, b string) error {
+	if _, found := u.phases[a]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	if _, found := u.phases[b]; !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	if a == b {
+		return fmt.Errorf("%w (id = %s)", common.ErrSelfDependency, a)
+	}
+	if u.strict {
+		if err := checkNewDependency(a, b, u.dependents.of); err != nil {
+			return err
+		}
+	}
 	u.deps = append(u.deps, dep{a, b})
+	u.dependents.add(a, b)
 	return nil
 }
 
 func (u *unixType) Linearize() ([]string, error) {
-	// Set up the tsort command and get the stdin pipe
-	cmd := exec.Command("tsort")
-	stdout := bytes.NewBuffer([]byte{})
-	stderr := bytes.NewBuffer([]byte{})
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, err
+	if len(u.ids) == 0 {
+		// There is nothing for tsort to do
+		return []string{}, nil
+	}
+	if u.inProcess {
+		return u.sortInProcess()
 	}
-	if err := cmd.Start(); err != nil {
+	return u.sortWithCommand()
+}
+
+// LinearizeInto copies the results of Linearize into dst,
+// so it still allocates as much as Linearize does.
+func (u *unixType) LinearizeInto(dst []string) ([]string, error) {
+	ids, err := u.Linearize()
+	if err != nil {
 		return nil, err
 	}
+	return append(dst[:0], ids...), nil
+}
 
-	// All the phases that aren't involved in any dependencies
-	// need to be added later.
+// pairs returns the input for tsort. All the phases that aren't involved
+// in any dependencies are paired with themselves, which tells tsort about
+// them without adding a dependency. They are added in the order they were
+// added so that the input to tsort (and hence the output) is always the same.
+func (u *unixType) pairs() []tsort.Pair {
+	pairs := make([]tsort.Pair, 0, len(u.deps))
 	leftOverPhases := map[string]struct{}{}
-	// Copy all phases into leftOverPhases
 	for p := range u.phases {
 		leftOverPhases[p] = struct{}{}
 	}
-
-	// Write all the dependency statements to stdin
 	for _, d := range u.deps {
-		stmt := fmt.Sprintf("%s %s ", d.depender, d.dependsOn)
-		stdin.Write([]byte(stmt))
-		// As we go, delete the phases that are in some dependency
-		// from the leftOverPhases. That way the only once left will
-		// be those that are not involved in any dependencies
-		delete(leftOverPhases, d.depender)
-		delete(leftOverPhases, d.dependsOn)
-	}
-	// Write all the left over phases to stdin
-	for p := range leftOverPhases {
-		stmt := fmt.Sprintf("%s %s ", p, p)
-		stdin.Write([]byte(stmt))
-	}
-
-	// Close pipe and wait for command to finish
-	if err := stdin.Close(); err != nil {
+		pairs = append(pairs, tsort.Pair{First: d.first, Second: d.second})
+		delete(leftOverPhases, d.first)
+		delete(leftOverPhases, d.second)
+	}
+	for _, p := range u.ids {
+		if _, found := leftOverPhases[p]; found {
+			pairs = append(pairs, tsort.Pair{First: p, Second: p})
+		}
+	}
+	return pairs
+}
+
+func (u *unixType) sortWithCommand() ([]string, error) {
+	stdin := bytes.NewBuffer([]byte{})
+	if err := tsort.Write(stdin, u.pairs()); err != nil {
 		return nil, err
 	}
-	if err := cmd.Wait(); err != nil {
+	cmd := exec.Command("tsort")
+	stdout := bytes.NewBuffer([]byte{})
+	stderr := bytes.NewBuffer([]byte{})
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
-			// tsort reported an error
-			return nil, fmt.Errorf("Error in tsort command: %s", stdout.String())
+			// tsort reported an error. Most likely it found a loop, but it
+			// only tells us about one of them, so we look for the cycles
+			// ourselves.
+			if err := u.cycleError(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("Error in tsort command: %s", stderr.String())
 		} else {
 			// There was some other problem
 			return nil, err
@@ -94,11 +162,36 @@ func (u *unixType) Linearize() ([]string, error) {
 	return ids, nil
 }
 
+func (u *unixType) sortInProcess() ([]string, error) {
+	ids, loops := tsort.Sort(u.pairs())
+	if len(loops) > 0 {
+		if err := u.cycleError(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error in tsort: found a loop %v", loops[0])
+	}
+	return ids, nil
+}
+
+// cycleError returns a CycleError describing the cycles between phases,
+// or nil if there are none.
+func (u *unixType) cycleError() error {
+	if err := common.NewCycleError(u.ids, u.dependents); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (u *unixType) Reset() {
-	u.deps = []dep{}
-	u.phases = map[string]struct{}{}
+	u.deps = u.deps[:0]
+	clear(u.dependents)
+	clear(u.phases)
+	u.ids = u.ids[:0]
 }
 
 func (u *unixType) String() string {
+	if u.inProcess {
+		return "Unix (in-process tsort) implementation"
+	}
 	return "Unix (builtin tsort) implementation"
 }
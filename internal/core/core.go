@@ -0,0 +1,152 @@
+// Package core holds the Presort and Kahn algorithms, written once over keys
+// of any comparable type K and values of any type V. implementations wraps
+// them with string keys and typed with the caller's own keys, so that both
+// work exactly the same way and a fix to one is a fix to the other. Errors
+// wrap the sentinels from common with the keys involved, formatted with %v.
+package core
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"strconv"
+)
+
+// Options changes the behavior of Presort and Kahn. See the options of the
+// same names in implementations.
+type Options[K comparable] struct {
+	// TieBreak sets the order of phases which are ready at the same time.
+	// It is only used by Kahn, and nil keeps them in the order they were
+	// added.
+	TieBreak func(a, b K) bool
+	// Strict makes AddDependency return an error without adding the
+	// dependency if it would close a loop.
+	Strict bool
+	// CycleError returns the error for a cycle, where cycle and components
+	// mean the same as the fields of common.CycleError.
+	CycleError func(cycle []K, components [][]K) error
+}
+
+// FindCycles looks for cycles between the phases with the given keys, where
+// dependents[i] holds the indexes of the phases which must run after
+// keys[i]. It returns the same cycle and components as common.NewCycleError,
+// or nil if there are none. The phases are passed to common.NewCycleError by
+// index, so keys don't need to be strings.
+func FindCycles[K comparable](keys []K, dependents [][]int) (cycle []K, components [][]K) {
+	ids := make([]string, len(keys))
+	for i := range keys {
+		ids[i] = strconv.Itoa(i)
+	}
+	edges := map[string][]string{}
+	for i, indexes := range dependents {
+		for _, j := range indexes {
+			edges[ids[i]] = append(edges[ids[i]], ids[j])
+		}
+	}
+	err := common.NewCycleError(ids, edges)
+	if err == nil {
+		return nil, nil
+	}
+	toKeys := func(ids []string) []K {
+		results := make([]K, len(ids))
+		for i, id := range ids {
+			index, _ := strconv.Atoi(id)
+			results[i] = keys[index]
+		}
+		return results
+	}
+	for _, component := range err.Components {
+		components = append(components, toKeys(component))
+	}
+	return toKeys(err.Cycle), components
+}
+
+// FindNewCycle returns the cycle which adding a dependency from a to b would
+// close, i.e. if a already depends (directly or indirectly) on b, along with
+// every phase on a path from b back to a. It returns nil if there is no such
+// cycle. The phases can be referred to by anything comparable, e.g. their
+// index or a pointer to them, so that callers can walk their own dependents
+// directly. dependentsOf returns the phases which depend directly on a phase,
+// and is called once for each phase the search visits, so it should not have
+// to search every phase to find them. The existing dependencies must not
+// contain any cycles, which is always true in strict mode.
+func FindNewCycle[P comparable](a, b P, dependentsOf func(P) []P) (cycle []P, component []P) {
+	// reaches holds whether each phase we have visited leads to a, and
+	// visited holds them in the order they were visited
+	reaches := map[P]bool{}
+	visited := []P{}
+	var visit func(p P) bool
+	visit = func(p P) bool {
+		if p == a {
+			return true
+		}
+		if result, found := reaches[p]; found {
+			return result
+		}
+		reaches[p] = false
+		visited = append(visited, p)
+		// Keep going after the first match, so that reaches is
+		// complete for every phase on a path back to a
+		for _, dependent := range dependentsOf(p) {
+			if visit(dependent) {
+				reaches[p] = true
+			}
+		}
+		return reaches[p]
+	}
+	if !visit(b) {
+		return nil, nil
+	}
+	// Follow the first phase which leads back to a at each step
+	cycle = []P{a}
+	for p := b; p != a; {
+		cycle = append(cycle, p)
+		for _, dependent := range dependentsOf(p) {
+			if dependent == a || reaches[dependent] {
+				p = dependent
+				break
+			}
+		}
+	}
+	// The new dependency would join a and every phase on a
+	// path from b back to a into a single component
+	component = []P{a}
+	for _, p := range visited {
+		if reaches[p] {
+			component = append(component, p)
+		}
+	}
+	return cycle, component
+}
+
+// unique returns keys without any repeats, keeping the
+// first occurrence of each key.
+func unique[K comparable](keys []K) []K {
+	seen := map[K]struct{}{}
+	results := []K{}
+	for _, key := range keys {
+		if _, found := seen[key]; !found {
+			seen[key] = struct{}{}
+			results = append(results, key)
+		}
+	}
+	return results
+}
+
+// resizeInts returns s with length n, reusing its memory if it is big
+// enough. The contents are not cleared.
+func resizeInts(s []int, n int) []int {
+	if cap(s) < n {
+		return make([]int, n)
+	}
+	return s[:n]
+}
+
+// appendEmpty appends an empty slice to s. If s has room, it reuses the
+// slice which was there before, which keeps its memory for later appends.
+func appendEmpty(s [][]int) [][]int {
+	if len(s) < cap(s) {
+		s = s[:len(s)+1]
+		s[len(s)-1] = s[len(s)-1][:0]
+		return s
+	}
+	return append(s, nil)
+}
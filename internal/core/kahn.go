@@ -0,0 +1,375 @@
+package core
+
+import (
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"iter"
+	"sort"
+)
+
+// Kahn is an implementation of Kahn's algorithm. See implementations.NewKahn
+// for how it works.
+type Kahn[K comparable, V any] struct {
+	// keys holds the key of every phase in the order they were added.
+	// Everything else refers to phases by their index in keys.
+	keys []K
+	// values holds the value of each phase
+	values []V
+	// indexes maps each phase to its index in keys
+	indexes map[K]int
+	// dependents holds the indexes of the phases which depend on
+	// each phase
+	dependents [][]int
+	// inDegrees holds the number of phases each phase depends on
+	inDegrees []int
+	// buffers holds the slices sortIndexes works in
+	buffers kahnBuffers
+	opts    Options[K]
+}
+
+// kahnBuffers holds slices which are kept between calls to Linearize,
+// so that it only has to allocate them when there are more phases
+type kahnBuffers struct {
+	remaining []int
+	levelOf   []int
+	queue     []int
+	order     []int
+	starts    []int
+}
+
+// NewKahn returns a new Kahn with the given options
+func NewKahn[K comparable, V any](opts Options[K]) *Kahn[K, V] {
+	return &Kahn[K, V]{
+		indexes: map[K]int{},
+		opts:    opts,
+	}
+}
+
+func (k *Kahn[K, V]) AddPhase(key K) error {
+	var zero V
+	return k.AddPhaseWithValue(key, zero)
+}
+
+func (k *Kahn[K, V]) AddPhaseWithValue(key K, value V) error {
+	if _, found := k.indexes[key]; found {
+		return fmt.Errorf("%w with id = %v", common.ErrDuplicatePhase, key)
+	}
+	k.indexes[key] = len(k.keys)
+	k.keys = append(k.keys, key)
+	k.values = append(k.values, value)
+	k.dependents = appendEmpty(k.dependents)
+	k.inDegrees = append(k.inDegrees, 0)
+	return nil
+}
+
+// AddPhaseID returns the index of the new phase as its handle
+func (k *Kahn[K, V]) AddPhaseID(key K) (common.PhaseID, error) {
+	if err := k.AddPhase(key); err != nil {
+		return 0, err
+	}
+	return common.PhaseID(len(k.keys) - 1), nil
+}
+
+func (k *Kahn[K, V]) Value(key K) (V, bool) {
+	index, found := k.indexes[key]
+	if !found {
+		var zero V
+		return zero, false
+	}
+	return k.values[index], true
+}
+
+func (k *Kahn[K, V]) AddDependency(a, b K) error {
+	aIndex, found := k.indexes[a]
+	if !found {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, a)
+	}
+	bIndex, found := k.indexes[b]
+	if !found {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, b)
+	}
+	return k.addDependency(aIndex, bIndex)
+}
+
+func (k *Kahn[K, V]) AddDependencyByID(a, b common.PhaseID) error {
+	if int(a) < 0 || int(a) >= len(k.keys) {
+		return fmt.Errorf("%w with handle = %d", common.ErrUnknownPhase, a)
+	}
+	if int(b) < 0 || int(b) >= len(k.keys) {
+		return fmt.Errorf("%w with handle = %d", common.ErrUnknownPhase, b)
+	}
+	return k.addDependency(int(a), int(b))
+}
+
+// addDependency adds a dependency between the phases at
+// the given indexes, which must both exist
+func (k *Kahn[K, V]) addDependency(aIndex, bIndex int) error {
+	if aIndex == bIndex {
+		return fmt.Errorf("%w (id = %v)", common.ErrSelfDependency, k.keys[aIndex])
+	}
+	if k.opts.Strict {
+		dependentsOf := func(index int) []int { return k.dependents[index] }
+		if cycle, component := FindNewCycle(aIndex, bIndex, dependentsOf); cycle != nil {
+			return k.opts.CycleError(k.keysOf(cycle), [][]K{k.keysOf(component)})
+		}
+	}
+	k.dependents[aIndex] = append(k.dependents[aIndex], bIndex)
+	k.inDegrees[bIndex]++
+	return nil
+}
+
+// keysOf returns the keys of the phases at the given indexes
+func (k *Kahn[K, V]) keysOf(indexes []int) []K {
+	keys := make([]K, len(indexes))
+	for j, i := range indexes {
+		keys[j] = k.keys[i]
+	}
+	return keys
+}
+
+func (k *Kahn[K, V]) Linearize() ([]K, error) {
+	return k.LinearizeInto(make([]K, 0, len(k.keys)))
+}
+
+// LinearizeInto doesn't allocate anything once dst and the buffers it
+// works in are big enough, unless a tie break is used.
+func (k *Kahn[K, V]) LinearizeInto(dst []K) ([]K, error) {
+	order, _, err := k.sortIndexes()
+	if err != nil {
+		return nil, err
+	}
+	dst = dst[:0]
+	for _, i := range order {
+		dst = append(dst, k.keys[i])
+	}
+	return dst, nil
+}
+
+// LinearizeIter walks the queue of ready phases one level at a time as it
+// is iterated over, so each phase is yielded as soon as its level is ready,
+// without waiting for the rest of the order. A cycle is only found once the
+// queue runs dry. Each iteration keeps its own count of the remaining
+// dependencies of each phase, so calling Linearize in the middle of it
+// doesn't change what it yields.
+func (k *Kahn[K, V]) LinearizeIter() iter.Seq2[K, error] {
+	return func(yield func(K, error) bool) {
+		remaining := append([]int{}, k.inDegrees...)
+		level, next := []int{}, []int{}
+		for i, inDegree := range remaining {
+			if inDegree == 0 {
+				level = append(level, i)
+			}
+		}
+		yielded := 0
+		for len(level) > 0 {
+			k.sortLevel(level)
+			next = next[:0]
+			for _, i := range level {
+				if !yield(k.keys[i], nil) {
+					return
+				}
+				yielded++
+				for _, dependent := range k.dependents[i] {
+					remaining[dependent]--
+					if remaining[dependent] == 0 {
+						next = append(next, dependent)
+					}
+				}
+			}
+			level, next = next, level
+		}
+		if yielded < len(k.keys) {
+			var zero K
+			yield(zero, k.cycleError(remaining))
+		}
+	}
+}
+
+// sortLevel puts the indexes of the phases in a level in the order they
+// were added, and then applies the tie break, which gives the same order
+// as sortIndexes.
+func (k *Kahn[K, V]) sortLevel(level []int) {
+	sort.Ints(level)
+	if k.opts.TieBreak != nil {
+		sort.SliceStable(level, func(x, y int) bool {
+			return k.opts.TieBreak(k.keys[level[x]], k.keys[level[y]])
+		})
+	}
+}
+
+func (k *Kahn[K, V]) LinearizeValues() ([]V, error) {
+	order, _, err := k.sortIndexes()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]V, len(order))
+	for j, i := range order {
+		results[j] = k.values[i]
+	}
+	return results, nil
+}
+
+func (k *Kahn[K, V]) LinearizeLevels() ([][]K, error) {
+	order, starts, err := k.sortIndexes()
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]K, len(starts)-1)
+	for l := range results {
+		results[l] = k.keysOf(order[starts[l]:starts[l+1]])
+	}
+	return results, nil
+}
+
+// sortIndexes returns the indexes of the phases sorted by level, where
+// level l is order[starts[l]:starts[l+1]]. Both slices are reused by the
+// next call.
+func (k *Kahn[K, V]) sortIndexes() (order []int, starts []int, err error) {
+	b := &k.buffers
+	b.remaining = append(b.remaining[:0], k.inDegrees...)
+	// levelOf holds the level of each phase, which is one more than
+	// the highest level of the phases it depends on
+	b.levelOf = resizeInts(b.levelOf, len(k.keys))
+	b.queue = b.queue[:0]
+	for i, inDegree := range b.remaining {
+		b.levelOf[i] = 0
+		if inDegree == 0 {
+			b.queue = append(b.queue, i)
+		}
+	}
+	numLevels := 0
+	for head := 0; head < len(b.queue); head++ {
+		i := b.queue[head]
+		if b.levelOf[i] >= numLevels {
+			numLevels = b.levelOf[i] + 1
+		}
+		for _, dependent := range k.dependents[i] {
+			if b.levelOf[dependent] <= b.levelOf[i] {
+				b.levelOf[dependent] = b.levelOf[i] + 1
+			}
+			b.remaining[dependent]--
+			if b.remaining[dependent] == 0 {
+				b.queue = append(b.queue, dependent)
+			}
+		}
+	}
+	if len(b.queue) < len(k.keys) {
+		return nil, nil, k.cycleError(b.remaining)
+	}
+	// Count the phases in each level to work out where each level starts,
+	// then walk the phases in the order they were added, which puts each
+	// level in that order too, without having to sort anything.
+	b.starts = resizeInts(b.starts, numLevels+1)
+	for l := range b.starts {
+		b.starts[l] = 0
+	}
+	for i := range k.keys {
+		b.starts[b.levelOf[i]+1]++
+	}
+	for l := 1; l <= numLevels; l++ {
+		b.starts[l] += b.starts[l-1]
+	}
+	// The queue isn't needed anymore, so it keeps track of
+	// the next free spot in each level
+	next := append(b.queue[:0], b.starts[:numLevels]...)
+	b.order = resizeInts(b.order, len(k.keys))
+	for i := range k.keys {
+		b.order[next[b.levelOf[i]]] = i
+		next[b.levelOf[i]]++
+	}
+	if k.opts.TieBreak != nil {
+		for l := 0; l < numLevels; l++ {
+			k.sortLevel(b.order[b.starts[l]:b.starts[l+1]])
+		}
+	}
+	return b.order, b.starts, nil
+}
+
+// cycleError returns an error describing the cycles between the phases
+// which never became ready, i.e. which still have remaining dependencies.
+func (k *Kahn[K, V]) cycleError(remaining []int) error {
+	// Only pass the phases which never became ready, renumbered
+	// by their position in keys
+	keys := []K{}
+	newIndexes := make([]int, len(k.keys))
+	for i, key := range k.keys {
+		newIndexes[i] = -1
+		if remaining[i] != 0 {
+			newIndexes[i] = len(keys)
+			keys = append(keys, key)
+		}
+	}
+	dependents := make([][]int, len(keys))
+	for i := range k.keys {
+		if newIndexes[i] == -1 {
+			continue
+		}
+		for _, dependent := range k.dependents[i] {
+			if newIndexes[dependent] != -1 {
+				dependents[newIndexes[i]] = append(dependents[newIndexes[i]], newIndexes[dependent])
+			}
+		}
+	}
+	if cycle, components := FindCycles(keys, dependents); cycle != nil {
+		return k.opts.CycleError(cycle, components)
+	}
+	return fmt.Errorf("Could not linearize phases %v", keys)
+}
+
+func (k *Kahn[K, V]) HasPhase(key K) bool {
+	_, found := k.indexes[key]
+	return found
+}
+
+func (k *Kahn[K, V]) Phases() []K {
+	return append([]K{}, k.keys...)
+}
+
+func (k *Kahn[K, V]) DependenciesOf(key K) []K {
+	index, found := k.indexes[key]
+	if !found {
+		return nil
+	}
+	results := []K{}
+	for i, other := range k.keys {
+		for _, dependent := range k.dependents[i] {
+			if dependent == index {
+				results = append(results, other)
+				break
+			}
+		}
+	}
+	return results
+}
+
+func (k *Kahn[K, V]) DependentsOf(key K) []K {
+	index, found := k.indexes[key]
+	if !found {
+		return nil
+	}
+	return unique(k.keysOf(k.dependents[index]))
+}
+
+func (k *Kahn[K, V]) NumPhases() int {
+	return len(k.keys)
+}
+
+func (k *Kahn[K, V]) NumDependencies() int {
+	count := 0
+	for i := range k.keys {
+		count += len(unique(k.dependents[i]))
+	}
+	return count
+}
+
+// Reset keeps the memory it has allocated so far, so that
+// building the same number of phases again doesn't allocate.
+func (k *Kahn[K, V]) Reset() {
+	clear(k.keys)
+	k.keys = k.keys[:0]
+	clear(k.values)
+	k.values = k.values[:0]
+	clear(k.indexes)
+	k.dependents = k.dependents[:0]
+	k.inDegrees = k.inDegrees[:0]
+}
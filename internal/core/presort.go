@@ -0,0 +1,504 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"iter"
+)
+
+// Presort keeps the phases in a linked list, in an order which satisfies
+// their dependencies whenever it can. Each new dependency moves one of its
+// phases if they are in the wrong order, so Linearize usually has nothing
+// left to do.
+type Presort[K comparable, V any] struct {
+	phases *list.List
+	// hasCycle is set when AddDependency could not find a way to
+	// satisfy a dependency by moving phases around. After that the
+	// order of phases is no longer maintained, and Linearize has to
+	// work out whether there really is a cycle.
+	hasCycle bool
+	// byKey maps the key of each phase to its element
+	byKey map[K]*list.Element
+	// elements holds the element of each phase, indexed by its
+	// handle. Removed phases leave a nil behind, so that the
+	// handles of the others stay the same.
+	elements []*list.Element
+	// spare holds the phases from before the last Reset, which
+	// AddPhase reuses along with the memory of their deps.
+	spare []*presortPhase[K, V]
+	opts  Options[K]
+}
+
+// NewPresort returns a new Presort with the given options
+func NewPresort[K comparable, V any](opts Options[K]) *Presort[K, V] {
+	return &Presort[K, V]{
+		phases: list.New(),
+		byKey:  map[K]*list.Element{},
+		opts:   opts,
+	}
+}
+
+type presortPhase[K comparable, V any] struct {
+	key    K
+	value  V
+	handle common.PhaseID
+	// position increases from the front of the list to the back, so
+	// that comparing positions tells which of two phases comes first
+	position int
+	deps     []*presortPhase[K, V]
+	// dependents holds the phases which depend on this one. It is
+	// only kept up to date in strict mode.
+	dependents []*presortPhase[K, V]
+}
+
+// positionGap is the difference between the positions of phases which
+// are added one after another, which leaves room to move others in
+// between them without renumbering the whole list.
+const positionGap = 1 << 16
+
+func (t *Presort[K, V]) AddPhase(key K) error {
+	var zero V
+	return t.AddPhaseWithValue(key, zero)
+}
+
+func (t *Presort[K, V]) AddPhaseWithValue(key K, value V) error {
+	if t.HasPhase(key) {
+		return fmt.Errorf("%w with id = %v", common.ErrDuplicatePhase, key)
+	}
+	var phase *presortPhase[K, V]
+	if n := len(t.spare); n > 0 {
+		phase = t.spare[n-1]
+		t.spare = t.spare[:n-1]
+	} else {
+		phase = &presortPhase[K, V]{}
+	}
+	phase.key, phase.value, phase.handle = key, value, common.PhaseID(len(t.elements))
+	// Phases without any dependencies go in front
+	e := t.phases.PushBack(phase)
+	t.place(e)
+	t.byKey[key] = e
+	t.elements = append(t.elements, e)
+	return nil
+}
+
+func (t *Presort[K, V]) AddPhaseID(key K) (common.PhaseID, error) {
+	if err := t.AddPhase(key); err != nil {
+		return 0, err
+	}
+	return common.PhaseID(len(t.elements) - 1), nil
+}
+
+func (t *Presort[K, V]) Value(key K) (V, bool) {
+	phase := t.find(key)
+	if phase == nil {
+		var zero V
+		return zero, false
+	}
+	return phase.value, true
+}
+
+func (t *Presort[K, V]) AddDependency(depKey, pKey K) error {
+	pEl, found := t.byKey[pKey]
+	if !found {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, pKey)
+	}
+	depEl, found := t.byKey[depKey]
+	if !found {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, depKey)
+	}
+	return t.addDependency(depEl, pEl)
+}
+
+func (t *Presort[K, V]) AddDependencyByID(depHandle, pHandle common.PhaseID) error {
+	depEl := t.element(depHandle)
+	if depEl == nil {
+		return fmt.Errorf("%w with handle = %d", common.ErrUnknownPhase, depHandle)
+	}
+	pEl := t.element(pHandle)
+	if pEl == nil {
+		return fmt.Errorf("%w with handle = %d", common.ErrUnknownPhase, pHandle)
+	}
+	return t.addDependency(depEl, pEl)
+}
+
+// addDependency records that the phase in pEl depends on the phase
+// in depEl, and moves one of them if p currently comes first.
+func (t *Presort[K, V]) addDependency(depEl, pEl *list.Element) error {
+	dep, p := depEl.Value.(*presortPhase[K, V]), pEl.Value.(*presortPhase[K, V])
+	if dep == p {
+		return fmt.Errorf("%w (id = %v)", common.ErrSelfDependency, p.key)
+	}
+	if t.opts.Strict {
+		// Any cycle is reported here, so if the phases can't be moved
+		// around below, hasCycle only means the order is stale.
+		dependentsOf := func(phase *presortPhase[K, V]) []*presortPhase[K, V] { return phase.dependents }
+		if cycle, component := FindNewCycle(dep, p, dependentsOf); cycle != nil {
+			return t.opts.CycleError(keysOf(cycle), [][]K{keysOf(component)})
+		}
+		dep.dependents = append(dep.dependents, p)
+	}
+	// We still need to record the dependency when hasCycle is set, so
+	// that Linearize can report the cycle (or sort the phases if there
+	// isn't one).
+	p.deps = append(p.deps, dep)
+	if t.hasCycle {
+		return nil
+	}
+	// Most of the time dep already comes before p (90% of the time it
+	// is!), in which case we don't need to change the order.
+	if dep.position < p.position {
+		return nil
+	}
+
+	// If p depends on dep and dep depends on p, we have a pretty clear cycle
+	for _, depdep := range dep.deps {
+		if depdep == p {
+			t.hasCycle = true
+			return nil
+		}
+	}
+	inBetweens := []*presortPhase[K, V]{}
+	for e := pEl.Next(); e != depEl; e = e.Next() {
+		inBetweens = append(inBetweens, e.Value.(*presortPhase[K, V]))
+	}
+	// First, we'll attempt to move p immediately after dep. We need
+	// to check any elements between p and dep to see if they depend on p
+	if !anyDependsOn(inBetweens, p) {
+		t.phases.MoveAfter(pEl, depEl)
+		t.place(pEl)
+		return nil
+	}
+	// Next, we'll attempt to move dep immediately before p. We need to
+	// make sure dep doesn't depend on any of the phases between p and dep
+	if dependsOnAny(dep, inBetweens) {
+		// If we've reached here, we cannot move p direcly after dep
+		// or dep directly before p, so we give up on the order.
+		t.hasCycle = true
+		return nil
+	}
+	t.phases.MoveBefore(depEl, pEl)
+	t.place(depEl)
+	return nil
+}
+
+// place gives the phase in e a position between the phases on either
+// side of it, or renumbers every phase if there is no room between them.
+func (t *Presort[K, V]) place(e *list.Element) {
+	p := e.Value.(*presortPhase[K, V])
+	prev, next := e.Prev(), e.Next()
+	switch {
+	case prev == nil && next == nil:
+		p.position = 0
+	case prev == nil:
+		p.position = next.Value.(*presortPhase[K, V]).position - positionGap
+	case next == nil:
+		p.position = prev.Value.(*presortPhase[K, V]).position + positionGap
+	default:
+		low, high := prev.Value.(*presortPhase[K, V]).position, next.Value.(*presortPhase[K, V]).position
+		if high-low < 2 {
+			t.renumber()
+			return
+		}
+		p.position = low + (high-low)/2
+	}
+}
+
+// renumber spreads out the positions of every phase again
+func (t *Presort[K, V]) renumber() {
+	position := 0
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		e.Value.(*presortPhase[K, V]).position = position
+		position += positionGap
+	}
+}
+
+// element returns the element of the phase with the
+// given handle, or nil if there is no such phase
+func (t *Presort[K, V]) element(handle common.PhaseID) *list.Element {
+	if int(handle) < 0 || int(handle) >= len(t.elements) {
+		return nil
+	}
+	return t.elements[handle]
+}
+
+func (t *Presort[K, V]) RemovePhase(key K) error {
+	toRemove, found := t.byKey[key]
+	if !found {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, key)
+	}
+	// Removing a phase can't break the order of the
+	// others, so all we need to do is forget about it.
+	removed := toRemove.Value.(*presortPhase[K, V])
+	delete(t.byKey, key)
+	t.elements[removed.handle] = nil
+	t.phases.Remove(toRemove)
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		p := e.Value.(*presortPhase[K, V])
+		p.deps = withoutPhase(p.deps, removed)
+		if t.opts.Strict {
+			p.dependents = withoutPhase(p.dependents, removed)
+		}
+	}
+	return nil
+}
+
+func (t *Presort[K, V]) RemoveDependency(depKey, pKey K) error {
+	p, dep := t.find(pKey), t.find(depKey)
+	if p == nil {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, pKey)
+	}
+	if dep == nil {
+		return fmt.Errorf("%w with id = %v", common.ErrUnknownPhase, depKey)
+	}
+	n := len(p.deps)
+	p.deps = withoutPhase(p.deps, dep)
+	if len(p.deps) == n {
+		return fmt.Errorf("%w %v -> %v", common.ErrUnknownDependency, depKey, pKey)
+	}
+	if t.opts.Strict {
+		dep.dependents = withoutPhase(dep.dependents, p)
+	}
+	return nil
+}
+
+// withoutPhase returns phases with every occurrence of
+// phase removed. It reuses the memory of phases.
+func withoutPhase[K comparable, V any](phases []*presortPhase[K, V], phase *presortPhase[K, V]) []*presortPhase[K, V] {
+	results := phases[:0]
+	for _, other := range phases {
+		if other != phase {
+			results = append(results, other)
+		}
+	}
+	return results
+}
+
+func (t *Presort[K, V]) Linearize() ([]K, error) {
+	// NOTE: LinearizeIter walks the linked list directly instead
+	// of building a slice of keys, which is even faster
+	return t.LinearizeInto(make([]K, 0, t.phases.Len()))
+}
+
+func (t *Presort[K, V]) LinearizeInto(dst []K) ([]K, error) {
+	if err := t.prepare(); err != nil {
+		return nil, err
+	}
+	dst = dst[:0]
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		dst = append(dst, e.Value.(*presortPhase[K, V]).key)
+	}
+	return dst, nil
+}
+
+// LinearizeIter walks the list of phases as it is iterated over. Unless
+// AddDependency gave up on keeping the phases in order, there is nothing
+// to do beforehand. Otherwise any cycle is found before the first phase.
+func (t *Presort[K, V]) LinearizeIter() iter.Seq2[K, error] {
+	return func(yield func(K, error) bool) {
+		if err := t.prepare(); err != nil {
+			var zero K
+			yield(zero, err)
+			return
+		}
+		for e := t.phases.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value.(*presortPhase[K, V]).key, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Presort[K, V]) LinearizeValues() ([]V, error) {
+	if err := t.prepare(); err != nil {
+		return nil, err
+	}
+	results := make([]V, 0, t.phases.Len())
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		results = append(results, e.Value.(*presortPhase[K, V]).value)
+	}
+	return results, nil
+}
+
+// prepare makes sure the phases are in order before they are returned.
+// If AddDependency gave up on keeping them in order, it either reports
+// the cycle or sorts the phases.
+func (t *Presort[K, V]) prepare() error {
+	if !t.hasCycle {
+		return nil
+	}
+	if err := t.cycleError(); err != nil {
+		return err
+	}
+	// The heuristic in AddDependency gave up, but there is no
+	// actual cycle. Sort the phases properly so we can carry on.
+	t.sort()
+	return nil
+}
+
+// cycleError returns an error describing the cycles between phases,
+// or nil if there are none.
+func (t *Presort[K, V]) cycleError() error {
+	keys := make([]K, 0, t.phases.Len())
+	indexes := map[*presortPhase[K, V]]int{}
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		p := e.Value.(*presortPhase[K, V])
+		indexes[p] = len(keys)
+		keys = append(keys, p.key)
+	}
+	dependents := make([][]int, len(keys))
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		p := e.Value.(*presortPhase[K, V])
+		for _, dep := range p.deps {
+			dependents[indexes[dep]] = append(dependents[indexes[dep]], indexes[p])
+		}
+	}
+	if cycle, components := FindCycles(keys, dependents); cycle != nil {
+		return t.opts.CycleError(cycle, components)
+	}
+	return nil
+}
+
+// sort reorders the phases with a depth-first search so that every phase
+// comes after its dependencies, and then clears hasCycle. It should only
+// be called once we know there are no cycles.
+func (t *Presort[K, V]) sort() {
+	elements := map[*presortPhase[K, V]]*list.Element{}
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		elements[e.Value.(*presortPhase[K, V])] = e
+	}
+	sorted := make([]*list.Element, 0, len(elements))
+	visited := map[*presortPhase[K, V]]struct{}{}
+	var visit func(p *presortPhase[K, V])
+	visit = func(p *presortPhase[K, V]) {
+		if _, found := visited[p]; found {
+			return
+		}
+		visited[p] = struct{}{}
+		for _, dep := range p.deps {
+			visit(dep)
+		}
+		sorted = append(sorted, elements[p])
+	}
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		visit(e.Value.(*presortPhase[K, V]))
+	}
+	for _, e := range sorted {
+		t.phases.MoveToBack(e)
+	}
+	t.renumber()
+	t.hasCycle = false
+}
+
+// anyDependsOn returns true iff any phase in phases depends on p
+func anyDependsOn[K comparable, V any](phases []*presortPhase[K, V], p *presortPhase[K, V]) bool {
+	for _, phase := range phases {
+		for _, dep := range phase.deps {
+			if dep == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependsOnAny returns true iff p depends on any phase in phases
+func dependsOnAny[K comparable, V any](p *presortPhase[K, V], phases []*presortPhase[K, V]) bool {
+	for _, phase := range phases {
+		for _, dep := range p.deps {
+			if dep == phase {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keysOf returns the keys of the given phases
+func keysOf[K comparable, V any](phases []*presortPhase[K, V]) []K {
+	keys := make([]K, len(phases))
+	for i, phase := range phases {
+		keys[i] = phase.key
+	}
+	return keys
+}
+
+func (t *Presort[K, V]) HasPhase(key K) bool {
+	return t.find(key) != nil
+}
+
+// Phases returns the keys of every phase, in the order they
+// are currently sorted in, which is not necessarily the
+// order in which they were added.
+func (t *Presort[K, V]) Phases() []K {
+	keys := []K{}
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*presortPhase[K, V]).key)
+	}
+	return keys
+}
+
+func (t *Presort[K, V]) DependenciesOf(key K) []K {
+	p := t.find(key)
+	if p == nil {
+		return nil
+	}
+	return unique(keysOf(p.deps))
+}
+
+func (t *Presort[K, V]) DependentsOf(key K) []K {
+	phase := t.find(key)
+	if phase == nil {
+		return nil
+	}
+	results := []K{}
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		p := e.Value.(*presortPhase[K, V])
+		for _, dep := range p.deps {
+			if dep == phase {
+				results = append(results, p.key)
+				break
+			}
+		}
+	}
+	return results
+}
+
+func (t *Presort[K, V]) NumPhases() int {
+	return t.phases.Len()
+}
+
+func (t *Presort[K, V]) NumDependencies() int {
+	count := 0
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		count += len(unique(e.Value.(*presortPhase[K, V]).deps))
+	}
+	return count
+}
+
+// find returns the phase with the given key, or nil if there is none
+func (t *Presort[K, V]) find(key K) *presortPhase[K, V] {
+	e, found := t.byKey[key]
+	if !found {
+		return nil
+	}
+	return e.Value.(*presortPhase[K, V])
+}
+
+// Reset keeps the phases so that AddPhase can reuse them, but
+// container/list has no way to reuse its elements, so AddPhase
+// still allocates one for each phase.
+func (t *Presort[K, V]) Reset() {
+	for e := t.phases.Front(); e != nil; e = e.Next() {
+		p := e.Value.(*presortPhase[K, V])
+		clear(p.deps)
+		clear(p.dependents)
+		*p = presortPhase[K, V]{deps: p.deps[:0], dependents: p.dependents[:0]}
+		t.spare = append(t.spare, p)
+	}
+	t.phases.Init()
+	clear(t.byKey)
+	clear(t.elements)
+	t.elements = t.elements[:0]
+	t.hasCycle = false
+}
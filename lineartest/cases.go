@@ -0,0 +1,85 @@
+package lineartest
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+)
+
+// dep defines a dependency by two
+// phase ids: first must run before second.
+// If you want to represent only one phase,
+// leave second blank
+type dep struct {
+	first  string
+	second string
+}
+
+// orderingCases are checked with common.Validate, so any
+// order which satisfies their dependencies is accepted
+var orderingCases = [][]dep{
+	{{"a", ""}},
+	{{"a", "b"}, {"b", "c"}},
+	{{"a", "b"}, {"b", "c"}, {"c", "d"}},
+	{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "d"}},
+	{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}},
+	// Several phases here could go in more than one order
+	{{"e", "b"}, {"d", ""}, {"a", "c"}, {"f", ""}, {"b", "c"}, {"g", "c"}},
+}
+
+// cycleDeps has a cycle a -> b -> c -> a, and
+// d and e which depend on it
+var cycleDeps = []dep{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "d"}, {"d", "e"}}
+
+// errorCases is a table of calls which should fail the same
+// way for every implementation. Each case starts with the
+// phases a and b, where a must run before b.
+var errorCases = []struct {
+	name     string
+	run      func(l common.Linearizer) error
+	expected error
+}{
+	{
+		name:     "AddPhase with an existing id",
+		run:      func(l common.Linearizer) error { return l.AddPhase("a") },
+		expected: common.ErrDuplicatePhase,
+	},
+	{
+		name:     "AddDependency on itself",
+		run:      func(l common.Linearizer) error { return l.AddDependency("a", "a") },
+		expected: common.ErrSelfDependency,
+	},
+	{
+		name:     "MustRunBefore itself",
+		run:      func(l common.Linearizer) error { return common.MustRunBefore(l, "b", "b") },
+		expected: common.ErrSelfDependency,
+	},
+	{
+		name:     "DependsOn itself",
+		run:      func(l common.Linearizer) error { return common.DependsOn(l, "b", "b") },
+		expected: common.ErrSelfDependency,
+	},
+	{
+		name:     "AddDependency with an unknown first phase",
+		run:      func(l common.Linearizer) error { return l.AddDependency("z", "a") },
+		expected: common.ErrUnknownPhase,
+	},
+	{
+		name:     "AddDependency with an unknown second phase",
+		run:      func(l common.Linearizer) error { return l.AddDependency("a", "z") },
+		expected: common.ErrUnknownPhase,
+	},
+	{
+		name:     "AddDependency on an unknown phase itself",
+		run:      func(l common.Linearizer) error { return l.AddDependency("z", "z") },
+		expected: common.ErrUnknownPhase,
+	},
+	{
+		name:     "MustRunBefore with an unknown phase",
+		run:      func(l common.Linearizer) error { return common.MustRunBefore(l, "a", "z") },
+		expected: common.ErrUnknownPhase,
+	},
+	{
+		name:     "DependsOn with an unknown phase",
+		run:      func(l common.Linearizer) error { return common.DependsOn(l, "z", "a") },
+		expected: common.ErrUnknownPhase,
+	},
+}
@@ -0,0 +1,267 @@
+// Package lineartest checks that an implementation of common.Linearizer
+// behaves the way the rest of the code expects. If you have written your
+// own implementation, you can test it like this:
+//
+//	func TestMyLinearizer(t *testing.T) {
+//		lineartest.RunConformance(t, NewMyLinearizer)
+//	}
+package lineartest
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"testing"
+)
+
+// RunConformance runs every conformance test as a subtest of t. Each test
+// gets its own Linearizer from newLinearizer. It covers:
+//
+//   - ordering of phases, including the direction of dependencies
+//   - cycles, which must be reported with a *common.CycleError, either
+//     by AddDependency or by Linearize
+//   - unknown phases, duplicate phases and self-dependencies, which
+//     must be reported with the errors in common
+//   - Reset, which must clear all previous phases
+//   - empty graphs, which must linearize to no phases
+func RunConformance(t *testing.T, newLinearizer common.Factory) {
+	t.Run("Ordering", func(t *testing.T) {
+		testOrdering(t, newLinearizer)
+	})
+	t.Run("Direction", func(t *testing.T) {
+		testDirection(t, newLinearizer)
+	})
+	t.Run("Cycle", func(t *testing.T) {
+		testCycle(t, newLinearizer)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		testErrors(t, newLinearizer)
+	})
+	t.Run("Reset", func(t *testing.T) {
+		testReset(t, newLinearizer)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		testEmpty(t, newLinearizer)
+	})
+}
+
+func testOrdering(t *testing.T, newLinearizer common.Factory) {
+	for _, deps := range orderingCases {
+		l := newLinearizer()
+		build(t, l, deps)
+		got := linearize(t, l)
+		validate(t, l, got, deps)
+		// Linearizing again should give the same results
+		compareResults(t, l, linearize(t, l), got)
+	}
+}
+
+// testDirection checks that each way of adding a dependency
+// puts the phases in the documented order.
+func testDirection(t *testing.T, newLinearizer common.Factory) {
+	cases := []struct {
+		method   string
+		add      func(l common.Linearizer) error
+		expected dep
+	}{
+		{"MustRunBefore", func(l common.Linearizer) error { return common.MustRunBefore(l, "a", "b") }, dep{"a", "b"}},
+		{"DependsOn", func(l common.Linearizer) error { return common.DependsOn(l, "a", "b") }, dep{"b", "a"}},
+		{"AddDependency", func(l common.Linearizer) error { return l.AddDependency("a", "b") }, dep{"a", "b"}},
+	}
+	for _, c := range cases {
+		l := newLinearizer()
+		build(t, l, []dep{{"a", ""}, {"b", ""}})
+		if err := c.add(l); err != nil {
+			t.Fatalf("%v failed during %s(\"a\", \"b\"): %s", l, c.method, err.Error())
+		}
+		validate(t, l, linearize(t, l), []dep{c.expected})
+	}
+}
+
+func testCycle(t *testing.T, newLinearizer common.Factory) {
+	l := newLinearizer()
+	err := buildCycle(t, l, cycleDeps)
+	if err == nil {
+		t.Fatalf("Expected error for cyclical graph but got none for %v", l)
+	}
+	var cycleErr *common.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError for %v but got %T: %s", l, err, err.Error())
+	}
+	checkCycle(t, l, cycleErr.Cycle, cycleDeps, []string{"a", "b", "c"})
+	if len(cycleErr.Components) != 1 {
+		t.Errorf("Expected exactly one cyclic component for %v but got: %v", l, cycleErr.Components)
+	}
+}
+
+func testErrors(t *testing.T, newLinearizer common.Factory) {
+	for _, tc := range errorCases {
+		l := newLinearizer()
+		build(t, l, []dep{{"a", "b"}})
+		if err := tc.run(l); !errors.Is(err, tc.expected) {
+			t.Errorf("%v: expected %q for %s but got: %v", l, tc.expected, tc.name, err)
+		}
+		// A call which failed should not have changed anything
+		validate(t, l, linearize(t, l), []dep{{"a", "b"}})
+	}
+}
+
+func testReset(t *testing.T, newLinearizer common.Factory) {
+	l := newLinearizer()
+	build(t, l, orderingCases[4])
+	l.Reset()
+	if got := linearize(t, l); len(got) != 0 {
+		t.Errorf("Expected no phases for %v after Reset but got: %v", l, got)
+	}
+	// The same ids can be used again, and none of the old
+	// dependencies should be left over.
+	deps := []dep{{"e", "d"}, {"d", "c"}}
+	build(t, l, deps)
+	validate(t, l, linearize(t, l), deps)
+
+	// A cycle should not outlive Reset either
+	l.Reset()
+	if err := buildCycle(t, l, cycleDeps); err == nil {
+		t.Fatalf("Expected error for cyclical graph but got none for %v", l)
+	}
+	l.Reset()
+	build(t, l, orderingCases[2])
+	validate(t, l, linearize(t, l), orderingCases[2])
+}
+
+func testEmpty(t *testing.T, newLinearizer common.Factory) {
+	l := newLinearizer()
+	if got := linearize(t, l); len(got) != 0 {
+		t.Errorf("Expected no phases for empty %v but got: %v", l, got)
+	}
+}
+
+// build adds the phases and dependencies in deps to l. Phases are
+// added in the order they first appear in deps, and then the
+// dependencies are added in order.
+func build(t *testing.T, l common.Linearizer, deps []dep) {
+	added := map[string]struct{}{}
+	for _, d := range deps {
+		for _, id := range []string{d.first, d.second} {
+			if _, found := added[id]; found || id == "" {
+				continue
+			}
+			added[id] = struct{}{}
+			if err := l.AddPhase(id); err != nil {
+				t.Fatalf("%v failed during AddPhase(%q) for test case: %v\nGot error: %s", l, id, deps, err.Error())
+			}
+		}
+	}
+	for _, d := range deps {
+		if d.second == "" {
+			continue
+		}
+		if err := l.AddDependency(d.first, d.second); err != nil {
+			t.Fatalf("%v failed during AddDependency(%q, %q) for test case: %v\nGot error: %s",
+				l, d.first, d.second, deps, err.Error())
+		}
+	}
+}
+
+// buildCycle adds the phases and dependencies in deps to l, where deps
+// contains a cycle, and returns the error which reports the cycle. Some
+// implementations report it from the AddDependency call which closes the
+// loop, in which case the remaining dependencies are not added. Others
+// report it from Linearize.
+func buildCycle(t *testing.T, l common.Linearizer, deps []dep) error {
+	phases, dependencies := convertDeps(deps)
+	for _, id := range phases {
+		if err := l.AddPhase(id); err != nil {
+			t.Fatalf("%v failed during AddPhase(%q) for test case: %v\nGot error: %s", l, id, deps, err.Error())
+		}
+	}
+	for _, d := range dependencies {
+		if err := l.AddDependency(d.Before, d.After); err != nil {
+			return err
+		}
+	}
+	_, err := l.Linearize()
+	return err
+}
+
+// linearize calls l.Linearize and fails the test if it returns an error
+func linearize(t *testing.T, l common.Linearizer) []string {
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%v failed during linearize\nGot error: %s", l, err.Error())
+	}
+	return got
+}
+
+// validate checks that got is a valid order for deps with common.Validate
+func validate(t *testing.T, l common.Linearizer, got []string, deps []dep) {
+	phases, dependencies := convertDeps(deps)
+	if err := common.Validate(phases, dependencies, got); err != nil {
+		t.Errorf("%v returned an invalid order for test case: %v\n\tGot: %v\n\tError: %s", l, deps, got, err.Error())
+	}
+}
+
+// convertDeps returns the phases in deps, in the order they first
+// appear, and the dependencies in deps as common.Dependencies.
+func convertDeps(deps []dep) ([]string, []common.Dependency) {
+	phases := []string{}
+	dependencies := []common.Dependency{}
+	added := map[string]struct{}{}
+	for _, d := range deps {
+		for _, id := range []string{d.first, d.second} {
+			if _, found := added[id]; found || id == "" {
+				continue
+			}
+			added[id] = struct{}{}
+			phases = append(phases, id)
+		}
+		if d.second != "" {
+			dependencies = append(dependencies, common.Dependency{Before: d.first, After: d.second})
+		}
+	}
+	return phases, dependencies
+}
+
+func compareResults(t *testing.T, l common.Linearizer, got []string, expected []string) {
+	if len(expected) != len(got) {
+		t.Errorf("Results were not the correct length for %v. Expected %d phases but got %d.\n\tExpected: %v\n\tGot: %v",
+			l, len(expected), len(got), expected, got)
+		return
+	}
+	for i, e := range expected {
+		g := got[i]
+		if e != g {
+			t.Errorf("Phase[%d] of results was incorrect for %v. Expected phase id = %s but got id = %s\n\tExpected: %v\n\tGot: %v",
+				i, l, e, g, expected, got)
+			return
+		}
+	}
+}
+
+// checkCycle checks that cycle consists of exactly the phases in expected
+// (in any rotation) and that each phase in cycle is followed by one of
+// its dependents in deps.
+func checkCycle(t *testing.T, l common.Linearizer, cycle []string, deps []dep, expected []string) {
+	if len(cycle) != len(expected) {
+		t.Fatalf("Cycle was not the correct length for %v.\n\tExpected: %v\n\tGot: %v", l, expected, cycle)
+	}
+	edges := map[dep]struct{}{}
+	for _, d := range deps {
+		edges[d] = struct{}{}
+	}
+	for i, id := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		if _, found := edges[dep{id, next}]; !found {
+			t.Errorf("Cycle for %v contained %s -> %s which is not a dependency.\n\tGot: %v", l, id, next, cycle)
+		}
+	}
+	remaining := map[string]struct{}{}
+	for _, id := range expected {
+		remaining[id] = struct{}{}
+	}
+	for _, id := range cycle {
+		delete(remaining, id)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Cycle for %v did not contain the expected phases.\n\tExpected: %v\n\tGot: %v", l, expected, cycle)
+	}
+}
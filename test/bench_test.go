@@ -3,6 +3,7 @@ package test
 import (
 	"github.com/albrow/dependency-linearization/common"
 	"github.com/albrow/dependency-linearization/implementations"
+	"strconv"
 	"testing"
 )
 
@@ -13,150 +14,311 @@ var (
 	tree1Deps    = makeTreeDeps(1)
 	tree3Deps    = makeTreeDeps(3)
 	tree10Deps   = makeTreeDeps(10)
+	tree100Deps  = makeTreeDeps(100)
 )
 
 func BenchmarkLinear1Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), linear1Deps)
 }
 
 func BenchmarkLinear1Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), linear1Deps)
+}
+
+func BenchmarkLinear1UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), linear1Deps)
 }
 
 func BenchmarkLinear1Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), linear1Deps)
 }
 
 func BenchmarkLinear1Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), linear1Deps)
 }
 
 func BenchmarkLinear1Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), linear1Deps)
 }
 
 func BenchmarkLinear1Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, linear1Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), linear1Deps)
+}
+
+func BenchmarkLinear1Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), linear1Deps)
+}
+
+func BenchmarkLinear1PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), linear1Deps)
 }
 
 func BenchmarkLinear3Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), linear3Deps)
 }
 
 func BenchmarkLinear3Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), linear3Deps)
+}
+
+func BenchmarkLinear3UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), linear3Deps)
 }
 
 func BenchmarkLinear3Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), linear3Deps)
 }
 
 func BenchmarkLinear3Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), linear3Deps)
 }
 
 func BenchmarkLinear3Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), linear3Deps)
 }
 
 func BenchmarkLinear3Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, linear3Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), linear3Deps)
+}
+
+func BenchmarkLinear3Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), linear3Deps)
+}
+
+func BenchmarkLinear3PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), linear3Deps)
 }
 
 func BenchmarkLinear10Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), linear10Deps)
 }
 
 func BenchmarkLinear10Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), linear10Deps)
+}
+
+func BenchmarkLinear10UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), linear10Deps)
 }
 
 func BenchmarkLinear10Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), linear10Deps)
 }
 
 func BenchmarkLinear10Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), linear10Deps)
 }
 
 func BenchmarkLinear10Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), linear10Deps)
 }
 
 func BenchmarkLinear10Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, linear10Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), linear10Deps)
+}
+
+func BenchmarkLinear10Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), linear10Deps)
+}
+
+func BenchmarkLinear10PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), linear10Deps)
+}
+
+func BenchmarkLinear10PresortIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), linear10Deps, false)
+}
+
+func BenchmarkLinear10PresortHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), linear10Deps, true)
+}
+
+func BenchmarkLinear10KahnIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), linear10Deps, false)
+}
+
+func BenchmarkLinear10KahnHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), linear10Deps, true)
+}
+
+func BenchmarkLinear10PresortInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewPresort(), linear10Deps)
+}
+
+func BenchmarkLinear10KahnInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewKahn(), linear10Deps)
+}
+
+func BenchmarkLinear10PearceKellyInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewPearceKelly(), linear10Deps)
+}
+
+func BenchmarkLinear10MapsInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewMaps(), linear10Deps)
+}
+
+func BenchmarkLinear10ListsInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewLists(), linear10Deps)
+}
+
+func BenchmarkLinear10PresortIter(b *testing.B) {
+	benchmarkLinearizeIter(b, implementations.NewPresort(), linear10Deps)
+}
+
+func BenchmarkLinear10KahnIter(b *testing.B) {
+	benchmarkLinearizeIter(b, implementations.NewKahn(), linear10Deps)
 }
 
 func BenchmarkTree1Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), tree1Deps)
 }
 
 func BenchmarkTree1Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), tree1Deps)
+}
+
+func BenchmarkTree1UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), tree1Deps)
 }
 
 func BenchmarkTree1Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), tree1Deps)
 }
 
 func BenchmarkTree1Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), tree1Deps)
 }
 
 func BenchmarkTree1Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), tree1Deps)
 }
 
 func BenchmarkTree1Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, tree1Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), tree1Deps)
+}
+
+func BenchmarkTree1Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), tree1Deps)
+}
+
+func BenchmarkTree1PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), tree1Deps)
 }
 
 func BenchmarkTree3Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), tree3Deps)
 }
 
 func BenchmarkTree3Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), tree3Deps)
+}
+
+func BenchmarkTree3UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), tree3Deps)
 }
 
 func BenchmarkTree3Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), tree3Deps)
 }
 
 func BenchmarkTree3Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), tree3Deps)
 }
 
 func BenchmarkTree3Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), tree3Deps)
 }
 
 func BenchmarkTree3Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, tree3Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), tree3Deps)
+}
+
+func BenchmarkTree3Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), tree3Deps)
+}
+
+func BenchmarkTree3PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), tree3Deps)
 }
 
 func BenchmarkTree10Goraph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Goraph, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewGoraph(), tree10Deps)
 }
 
 func BenchmarkTree10Unix(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Unix, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewUnix(unixOptions()...), tree10Deps)
+}
+
+func BenchmarkTree10UnixInProcess(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewUnix(implementations.InProcess()), tree10Deps)
 }
 
 func BenchmarkTree10Graph(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Graph, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewGraph(), tree10Deps)
 }
 
 func BenchmarkTree10Maps(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Maps, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewMaps(), tree10Deps)
 }
 
 func BenchmarkTree10Lists(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Lists, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewLists(), tree10Deps)
 }
 
 func BenchmarkTree10Presort(b *testing.B) {
-	benchmarkLinearizer(b, implementations.Presort, tree10Deps)
+	benchmarkLinearizer(b, implementations.NewPresort(), tree10Deps)
+}
+
+func BenchmarkTree10Kahn(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewKahn(), tree10Deps)
+}
+
+func BenchmarkTree10PearceKelly(b *testing.B) {
+	benchmarkLinearizer(b, implementations.NewPearceKelly(), tree10Deps)
+}
+
+func BenchmarkTree10PresortIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), tree10Deps, false)
+}
+
+func BenchmarkTree10PresortHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), tree10Deps, true)
+}
+
+func BenchmarkTree10KahnIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), tree10Deps, false)
+}
+
+func BenchmarkTree10KahnHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), tree10Deps, true)
+}
+
+func BenchmarkTree10PresortInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewPresort(), tree10Deps)
+}
+
+func BenchmarkTree10KahnInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewKahn(), tree10Deps)
+}
+
+func BenchmarkTree10PearceKellyInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewPearceKelly(), tree10Deps)
+}
+
+func BenchmarkTree10MapsInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewMaps(), tree10Deps)
+}
+
+func BenchmarkTree10ListsInto(b *testing.B) {
+	benchmarkLinearizeInto(b, implementations.NewLists(), tree10Deps)
+}
+
+func BenchmarkTree10PresortIter(b *testing.B) {
+	benchmarkLinearizeIter(b, implementations.NewPresort(), tree10Deps)
+}
+
+func BenchmarkTree10KahnIter(b *testing.B) {
+	benchmarkLinearizeIter(b, implementations.NewKahn(), tree10Deps)
 }
 
 // benchmarkLinearizer runs the given deps list through
@@ -167,6 +329,7 @@ func BenchmarkTree10Presort(b *testing.B) {
 func benchmarkLinearizer(b *testing.B, l common.Linearizer, deps []dep) {
 	p := prepareCase(l, deps)
 	funcs := p.getFuncs()
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, f := range funcs {
@@ -186,3 +349,204 @@ func benchmarkLinearizer(b *testing.B, l common.Linearizer, deps []dep) {
 		b.StartTimer()
 	}
 }
+
+func BenchmarkTree100PresortIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), tree100Deps, false)
+}
+
+func BenchmarkTree100PresortHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewPresort(), tree100Deps, true)
+}
+
+func BenchmarkTree100KahnIds(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), tree100Deps, false)
+}
+
+func BenchmarkTree100KahnHandles(b *testing.B) {
+	benchmarkBuild(b, implementations.NewKahn(), tree100Deps, true)
+}
+
+// benchmarkBuild adds the phases and dependencies in deps to l, either by
+// id or with AddPhaseID and AddDependencyByID, and then linearizes it.
+// Stopping the timer takes longer than adding a dependency, so unlike
+// benchmarkLinearizer, it leaves the timer running around each call. That
+// way the ...Ids and ...Handles benchmarks show the difference between
+// looking up phases by id and by handle.
+func benchmarkBuild(b *testing.B, l common.Linearizer, deps []dep, byHandle bool) {
+	h := l.(common.Handler)
+	// Work out the order of the phases and the indexes of the
+	// phases in each dependency ahead of time
+	ids := []string{}
+	indexes := map[string]int{}
+	addId := func(id string) {
+		if _, found := indexes[id]; !found && id != "" {
+			indexes[id] = len(ids)
+			ids = append(ids, id)
+		}
+	}
+	for _, d := range deps {
+		addId(d.first)
+		addId(d.second)
+	}
+	pairs := [][2]int{}
+	for _, d := range deps {
+		if d.second != "" {
+			pairs = append(pairs, [2]int{indexes[d.first], indexes[d.second]})
+		}
+	}
+	handles := make([]common.PhaseID, len(ids))
+	dst := []string{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, id := range ids {
+			var err error
+			if byHandle {
+				handles[j], err = h.AddPhaseID(id)
+			} else {
+				err = l.AddPhase(id)
+			}
+			if err != nil {
+				panic(err)
+			}
+		}
+		for _, pair := range pairs {
+			var err error
+			if byHandle {
+				err = h.AddDependencyByID(handles[pair[0]], handles[pair[1]])
+			} else {
+				err = l.AddDependency(ids[pair[0]], ids[pair[1]])
+			}
+			if err != nil {
+				panic(err)
+			}
+		}
+		var err error
+		dst, err = l.(common.IntoLinearizer).LinearizeInto(dst)
+		if err != nil {
+			panic(err)
+		}
+		l.Reset()
+	}
+}
+
+// benchmarkLinearizeInto is the same as benchmarkLinearizer, but reuses
+// the same slice for the results with LinearizeInto
+func benchmarkLinearizeInto(b *testing.B, l common.Linearizer, deps []dep) {
+	into := l.(common.IntoLinearizer)
+	p := prepareCase(l, deps)
+	funcs := p.getFuncs()
+	dst := []string{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range funcs {
+			err := f()
+			b.StopTimer()
+			if err != nil {
+				panic(err)
+			}
+			b.StartTimer()
+		}
+		var err error
+		dst, err = into.LinearizeInto(dst)
+		b.StopTimer()
+		if err != nil {
+			panic(err)
+		}
+		l.Reset()
+		b.StartTimer()
+	}
+}
+
+// benchmarkLinearizeIter is the same as benchmarkLinearizer, but
+// ranges over the results of LinearizeIter instead of a slice
+func benchmarkLinearizeIter(b *testing.B, l common.Linearizer, deps []dep) {
+	it := l.(common.Iterator)
+	p := prepareCase(l, deps)
+	funcs := p.getFuncs()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range funcs {
+			err := f()
+			b.StopTimer()
+			if err != nil {
+				panic(err)
+			}
+			b.StartTimer()
+		}
+		for _, err := range it.LinearizeIter() {
+			if err != nil {
+				panic(err)
+			}
+		}
+		b.StopTimer()
+		l.Reset()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkStrictChainGoraph(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewGoraph(implementations.Strict()))
+}
+
+func BenchmarkStrictChainUnix(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewUnix(unixOptions(implementations.Strict())...))
+}
+
+func BenchmarkStrictChainGraph(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewGraph(implementations.Strict()))
+}
+
+func BenchmarkStrictChainMaps(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewMaps(implementations.Strict()))
+}
+
+func BenchmarkStrictChainLists(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewLists(implementations.Strict()))
+}
+
+func BenchmarkStrictChainPresort(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewPresort(implementations.Strict()))
+}
+
+func BenchmarkStrictChainKahn(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewKahn(implementations.Strict()))
+}
+
+func BenchmarkStrictChainPearceKelly(b *testing.B) {
+	benchmarkStrictChain(b, implementations.NewPearceKelly())
+}
+
+// strictChainLength is the number of phases in the chain
+// which benchmarkStrictChain builds
+const strictChainLength = 1000
+
+// benchmarkStrictChain adds a chain of phases, 0 -> 1 -> 2 -> ..., to l
+// starting from the end, so that in strict mode each new dependency has to
+// search the whole rest of the chain for a cycle. That makes building the
+// chain quadratic, but it should be no worse than that, i.e. looking up the
+// dependents of each phase which the search visits should not have to look
+// at every phase.
+func benchmarkStrictChain(b *testing.B, l common.Linearizer) {
+	ids := make([]string, strictChainLength)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range ids {
+			if err := l.AddPhase(id); err != nil {
+				panic(err)
+			}
+		}
+		for j := len(ids) - 2; j >= 0; j-- {
+			if err := l.AddDependency(ids[j], ids[j+1]); err != nil {
+				panic(err)
+			}
+		}
+		l.Reset()
+	}
+}
@@ -0,0 +1,224 @@
+package test
+
+import (
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const (
+	numWorkers      = 8
+	phasesPerWorker = 10
+)
+
+// TestSynchronized hammers each implementation, wrapped with common.Synchronized,
+// from many goroutines at once. Run it with go test -race to catch any access
+// which is not synchronized.
+func TestSynchronized(t *testing.T) {
+	for _, newLinearizer := range factories {
+		l := common.Synchronized(newLinearizer())
+		hammerBuild(t, l)
+		hammerLinearizeAndReset(l)
+	}
+}
+
+// hammerBuild has each worker add its own chain of phases, i.e. w-0 -> w-1 -> ...,
+// to l at the same time and then checks that every chain was linearized in order.
+// The workers never depend on each other's phases, but they all share l.
+func hammerBuild(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	var wg sync.WaitGroup
+	errs := make(chan error, numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < phasesPerWorker; i++ {
+				id := fmt.Sprintf("%d-%d", w, i)
+				if err := l.AddPhase(id); err != nil {
+					errs <- err
+					return
+				}
+				if i == 0 {
+					continue
+				}
+				if err := l.AddDependency(fmt.Sprintf("%d-%d", w, i-1), id); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("%s failed while adding phases concurrently: %s", l, err.Error())
+	}
+
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize: %s", l, err.Error())
+	}
+	if len(got) != numWorkers*phasesPerWorker {
+		t.Fatalf("Results were not the correct length for %s. Expected %d phases but got %d.\n\tGot: %v",
+			l, numWorkers*phasesPerWorker, len(got), got)
+	}
+	positions := map[string]int{}
+	for i, id := range got {
+		positions[id] = i
+	}
+	for w := 0; w < numWorkers; w++ {
+		for i := 1; i < phasesPerWorker; i++ {
+			before, after := fmt.Sprintf("%d-%d", w, i-1), fmt.Sprintf("%d-%d", w, i)
+			if positions[before] > positions[after] {
+				t.Errorf("Expected %s to come before %s for %s.\n\tGot: %v", before, after, l, got)
+			}
+		}
+	}
+}
+
+// hammerLinearizeAndReset calls every method of l from many goroutines at once.
+// The results depend on how the calls happen to interleave, so they are ignored.
+// The point is to give the race detector something to chew on.
+func hammerLinearizeAndReset(l common.Linearizer) {
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				id := fmt.Sprintf("%d-%d", w, i)
+				l.AddPhase(id)
+				l.AddDependency(fmt.Sprintf("%d-%d", w, i-1), id)
+				l.Linearize()
+				if leveler, ok := l.(common.Leveler); ok {
+					leveler.LinearizeLevels()
+				}
+				if into, ok := l.(common.IntoLinearizer); ok {
+					into.LinearizeInto(nil)
+				}
+				if it, ok := l.(common.Iterator); ok {
+					for range it.LinearizeIter() {
+					}
+				}
+				if w%2 == 0 {
+					l.Reset()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	l.Reset()
+}
+
+// TestSynchronizedInterfaces checks that every optional interface an
+// implementation supports still works after wrapping it with
+// common.Synchronized, by making the same calls to a wrapped and an
+// unwrapped linearizer and comparing the results. Interfaces which the
+// implementation does not support shouldn't be implemented by the wrapper
+// either, so that checking for them gives the right answer.
+func TestSynchronizedInterfaces(t *testing.T) {
+	for _, newLinearizer := range factories {
+		plain, wrapped := newLinearizer(), common.Synchronized(newLinearizer())
+		deps := []dep{{"a", "b"}, {"a", "c"}, {"c", "d"}}
+		for _, l := range []common.Linearizer{plain, wrapped} {
+			if err := prepareCase(l, deps).execute(); err != nil {
+				t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+			}
+		}
+		// same checks that the wrapped results match the unwrapped ones
+		same := func(method string, expected, got interface{}) {
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("%s returned different results from %s than %s.\n\tExpected: %v\n\tGot: %v",
+					wrapped, method, plain, expected, got)
+			}
+		}
+		// wrapped should implement each interface iff plain does
+		mismatched := false
+		for name, implementsInterface := range map[string]func(common.Linearizer) bool{
+			"Leveler":        func(l common.Linearizer) bool { _, ok := l.(common.Leveler); return ok },
+			"Mutable":        func(l common.Linearizer) bool { _, ok := l.(common.Mutable); return ok },
+			"Valuer":         func(l common.Linearizer) bool { _, ok := l.(common.Valuer); return ok },
+			"IntoLinearizer": func(l common.Linearizer) bool { _, ok := l.(common.IntoLinearizer); return ok },
+			"Handler":        func(l common.Linearizer) bool { _, ok := l.(common.Handler); return ok },
+			"Iterator":       func(l common.Linearizer) bool { _, ok := l.(common.Iterator); return ok },
+			"Inspector":      func(l common.Linearizer) bool { _, ok := l.(common.Inspector); return ok },
+		} {
+			if plainOk, wrappedOk := implementsInterface(plain), implementsInterface(wrapped); plainOk && !wrappedOk {
+				t.Errorf("%s does not implement common.%s, even though %s does", wrapped, name, plain)
+				mismatched = true
+			} else if !plainOk && wrappedOk {
+				t.Errorf("%s implements common.%s, even though %s does not", wrapped, name, plain)
+				mismatched = true
+			}
+		}
+		if mismatched {
+			continue
+		}
+
+		if v, ok := plain.(common.Valuer); ok {
+			same("AddPhaseWithValue", v.AddPhaseWithValue("e", 1), wrapped.(common.Valuer).AddPhaseWithValue("e", 1))
+			value, found := v.Value("e")
+			wrappedValue, wrappedFound := wrapped.(common.Valuer).Value("e")
+			same("Value", []interface{}{value, found}, []interface{}{wrappedValue, wrappedFound})
+		}
+		if h, ok := plain.(common.Handler); ok {
+			handle, err := h.AddPhaseID("f")
+			wrappedHandle, wrappedErr := wrapped.(common.Handler).AddPhaseID("f")
+			same("AddPhaseID", []interface{}{handle, err}, []interface{}{wrappedHandle, wrappedErr})
+			a, _ := h.AddPhaseID("g")
+			wrappedA, _ := wrapped.(common.Handler).AddPhaseID("g")
+			same("AddDependencyByID", h.AddDependencyByID(a, handle), wrapped.(common.Handler).AddDependencyByID(wrappedA, wrappedHandle))
+		}
+		if m, ok := plain.(common.Mutable); ok {
+			same("RemoveDependency", m.RemoveDependency("c", "d"), wrapped.(common.Mutable).RemoveDependency("c", "d"))
+			same("RemovePhase", m.RemovePhase("b"), wrapped.(common.Mutable).RemovePhase("b"))
+		}
+
+		expected, err := plain.Linearize()
+		if err != nil {
+			t.Fatalf("%s failed during Linearize: %s", plain, err.Error())
+		}
+		got, err := wrapped.Linearize()
+		if err != nil {
+			t.Fatalf("%s failed during Linearize: %s", wrapped, err.Error())
+		}
+		same("Linearize", expected, got)
+		if v, ok := plain.(common.Valuer); ok {
+			values, _ := v.LinearizeValues()
+			wrappedValues, _ := wrapped.(common.Valuer).LinearizeValues()
+			same("LinearizeValues", values, wrappedValues)
+		}
+		if leveler, ok := plain.(common.Leveler); ok {
+			levels, _ := leveler.LinearizeLevels()
+			wrappedLevels, _ := wrapped.(common.Leveler).LinearizeLevels()
+			same("LinearizeLevels", levels, wrappedLevels)
+		}
+		if into, ok := plain.(common.IntoLinearizer); ok {
+			ids, _ := into.LinearizeInto(nil)
+			wrappedIds, _ := wrapped.(common.IntoLinearizer).LinearizeInto(nil)
+			same("LinearizeInto", ids, wrappedIds)
+		}
+		if it, ok := plain.(common.Iterator); ok {
+			ids, wrappedIds := []string{}, []string{}
+			for id := range it.LinearizeIter() {
+				ids = append(ids, id)
+			}
+			for id := range wrapped.(common.Iterator).LinearizeIter() {
+				wrappedIds = append(wrappedIds, id)
+			}
+			same("LinearizeIter", ids, wrappedIds)
+		}
+		if in, ok := plain.(common.Inspector); ok {
+			wrappedIn := wrapped.(common.Inspector)
+			same("Phases", in.Phases(), wrappedIn.Phases())
+			same("HasPhase", in.HasPhase("a"), wrappedIn.HasPhase("a"))
+			same("DependenciesOf", in.DependenciesOf("c"), wrappedIn.DependenciesOf("c"))
+			same("DependentsOf", in.DependentsOf("a"), wrappedIn.DependentsOf("a"))
+			same("NumPhases", in.NumPhases(), wrappedIn.NumPhases())
+			same("NumDependencies", in.NumDependencies(), wrappedIn.NumDependencies())
+		}
+	}
+}
@@ -3,99 +3,121 @@ package test
 import (
 	"github.com/albrow/dependency-linearization/common"
 	"github.com/albrow/dependency-linearization/implementations"
-	"strings"
+	"github.com/albrow/dependency-linearization/lineartest"
 	"testing"
 )
 
-var testCases = []testCase{
-	{
-		deps:     []dep{{"a", ""}},
-		expected: []string{"a"},
-	},
-	{
-		deps:     []dep{{"a", "b"}, {"b", "c"}},
-		expected: []string{"a", "b", "c"},
-	},
-	{
-		deps:     []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}},
-		expected: []string{"a", "b", "c", "d"},
-	},
-	{
-		deps:     []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "d"}},
-		expected: []string{"a", "b", "c", "d"},
-	},
-	{
-		deps:     []dep{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}},
-		expected: []string{"a", "b", "c", "d", "e"},
-	},
-}
-
 func TestGoraph(t *testing.T) {
-	testLinearizer(t, implementations.Goraph)
-}
-
-func TestGoraphCycle(t *testing.T) {
-	testCycle(t, implementations.Goraph)
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewGoraph() })
 }
 
 func TestUnix(t *testing.T) {
-	testLinearizer(t, implementations.Unix)
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewUnix(unixOptions()...) })
 }
 
-func TestUnixCycle(t *testing.T) {
-	testCycle(t, implementations.Unix)
+func TestUnixInProcess(t *testing.T) {
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewUnix(implementations.InProcess()) })
 }
 
 func TestGraph(t *testing.T) {
-	testLinearizer(t, implementations.Graph)
-}
-
-func TestGraphCycle(t *testing.T) {
-	testCycle(t, implementations.Graph)
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewGraph() })
 }
 
 func TestMaps(t *testing.T) {
-	testLinearizer(t, implementations.Maps)
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewMaps() })
 }
 
-func TestMapsCycle(t *testing.T) {
-	testCycle(t, implementations.Maps)
+func TestLists(t *testing.T) {
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewLists() })
 }
 
-func TestLists(t *testing.T) {
-	testLinearizer(t, implementations.Lists)
+func TestPresort(t *testing.T) {
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewPresort() })
 }
 
-func TestListsCycle(t *testing.T) {
-	testCycle(t, implementations.Lists)
+func TestKahn(t *testing.T) {
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewKahn() })
 }
 
-func TestPresort(t *testing.T) {
-	testLinearizer(t, implementations.Presort)
+func TestPearceKelly(t *testing.T) {
+	lineartest.RunConformance(t, func() common.Linearizer { return implementations.NewPearceKelly() })
 }
 
-func TestPresortCycle(t *testing.T) {
-	testCycle(t, implementations.Presort)
+// factories holds a constructor with the default
+// options for each implementation
+var factories = []common.Factory{
+	func() common.Linearizer { return implementations.NewGoraph() },
+	func() common.Linearizer { return implementations.NewUnix(unixOptions()...) },
+	func() common.Linearizer { return implementations.NewGraph() },
+	func() common.Linearizer { return implementations.NewMaps() },
+	func() common.Linearizer { return implementations.NewLists() },
+	func() common.Linearizer { return implementations.NewPresort() },
+	func() common.Linearizer { return implementations.NewKahn() },
+	func() common.Linearizer { return implementations.NewPearceKelly() },
 }
 
-func testLinearizer(t *testing.T, l common.Linearizer) {
-	for _, tc := range testCases {
-		runTestCase(t, l, tc)
+func TestIndependentInstances(t *testing.T) {
+	firstCase := testCase{
+		deps:     []dep{{"a", "b"}, {"b", "c"}},
+		expected: []string{"a", "b", "c"},
+	}
+	secondCase := testCase{
+		deps:     []dep{{"c", "b"}, {"b", "a"}, {"a", "d"}},
+		expected: []string{"c", "b", "a", "d"},
+	}
+	for _, newLinearizer := range factories {
+		// Build two different cases side by side. If the instances shared
+		// any state, the phases of one would show up in the other.
+		first, second := newLinearizer(), newLinearizer()
+		if err := prepareCase(first, firstCase.deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", first, firstCase.deps, err.Error())
+		}
+		if err := prepareCase(second, secondCase.deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", second, secondCase.deps, err.Error())
+		}
+		for _, pair := range []struct {
+			l  common.Linearizer
+			tc testCase
+		}{{first, firstCase}, {second, secondCase}} {
+			got, err := pair.l.Linearize()
+			if err != nil {
+				t.Fatalf("%s failed during linearize for test case: %v\nGot error: %s", pair.l, pair.tc.deps, err.Error())
+			}
+			compareResults(t, pair.l, got, pair.tc.expected)
+		}
 	}
 }
 
-func testCycle(t *testing.T, l common.Linearizer) {
-	// There is a cycle a -> b -> c -> a
-	deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "d"}, {"d", "e"}}
-	if err := prepareCase(l, deps).execute(); err != nil {
-		panic(err)
+func TestNewCycleError(t *testing.T) {
+	// There are two cycles: a -> b -> a and c -> d -> e -> c, plus a
+	// shortcut c -> e. f depends on the first cycle but is not part of it.
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	edges := map[string][]string{
+		"a": {"b"},
+		"b": {"a", "f"},
+		"c": {"d", "e"},
+		"d": {"e"},
+		"e": {"c"},
 	}
-	if _, err := l.Linearize(); err == nil {
-		t.Error("Expected error for cyclical graph but got none")
-	} else {
-		if !strings.Contains(err.Error(), "cycle") {
-			t.Errorf("Expected error to say something about a cycle but it did not. Got: %s", err.Error())
-		}
+	err := common.NewCycleError(ids, edges)
+	if err == nil {
+		t.Fatal("Expected a CycleError but got nil")
+	}
+	expectedComponents := [][]string{{"a", "b"}, {"c", "d", "e"}}
+	if len(err.Components) != len(expectedComponents) {
+		t.Fatalf("Expected components %v but got %v", expectedComponents, err.Components)
+	}
+	for i, expected := range expectedComponents {
+		compareResults(t, nil, err.Components[i], expected)
+	}
+	compareResults(t, nil, err.Cycle, []string{"a", "b"})
+	if got, expected := err.Error(), "Detected cycle: a -> b -> a"; got != expected {
+		t.Errorf("Expected error message %q but got %q", expected, got)
+	}
+
+	delete(edges, "b")
+	delete(edges, "e")
+	if err := common.NewCycleError(ids, edges); err != nil {
+		t.Errorf("Expected no CycleError for acyclic graph but got: %v", err)
 	}
-	l.Reset()
 }
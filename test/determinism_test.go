@@ -0,0 +1,65 @@
+package test
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+// numDeterminismRuns is the number of times each
+// implementation runs the same case in TestDeterminism
+const numDeterminismRuns = 200
+
+// determinismDeps has a few phases which do not depend on
+// each other, so there is more than one valid order.
+var determinismDeps = []dep{{"e", "b"}, {"d", ""}, {"a", "c"}, {"f", ""}, {"b", "c"}, {"g", "c"}}
+
+func TestDeterminism(t *testing.T) {
+	for _, newLinearizer := range factories {
+		var first []string
+		for i := 0; i < numDeterminismRuns; i++ {
+			// Use a new linearizer each time so that nothing can be
+			// left over from the previous run.
+			l := newLinearizer()
+			if err := prepareCase(l, determinismDeps).execute(); err != nil {
+				t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, determinismDeps, err.Error())
+			}
+			got, err := l.Linearize()
+			if err != nil {
+				t.Fatalf("%s failed during linearize for test case: %v\nGot error: %s", l, determinismDeps, err.Error())
+			}
+			if i == 0 {
+				first = got
+				continue
+			}
+			compareResults(t, l, got, first)
+			if t.Failed() {
+				t.Fatalf("%s returned a different order on run %d", l, i)
+			}
+		}
+	}
+}
+
+func TestMapsInsertionOrder(t *testing.T) {
+	testInsertionOrder(t, implementations.NewMaps())
+}
+
+func TestListsInsertionOrder(t *testing.T) {
+	testInsertionOrder(t, implementations.NewLists())
+}
+
+func TestKahnInsertionOrder(t *testing.T) {
+	testInsertionOrder(t, implementations.NewKahn())
+}
+
+// testInsertionOrder checks that phases which are ready at
+// the same time keep the order in which they were added.
+func testInsertionOrder(t *testing.T, l common.Linearizer) {
+	// prepareCase adds phases in the order they first appear,
+	// i.e. e, b, d, a, c, f, g. e, d, a, f and g are ready
+	// first, then b, and then c.
+	runTestCase(t, l, testCase{
+		deps:     determinismDeps,
+		expected: []string{"e", "d", "a", "f", "g", "b", "c"},
+	})
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	l := implementations.NewMaps()
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"say \"hi\"", ""}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	order, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize: %s", l, err.Error())
+	}
+	checkDOT(t, l, common.DOTOptions{Order: order}, `digraph dependencies {
+	"a" [label="a (1)"];
+	"b" [label="b (3)"];
+	"c" [label="c (4)"];
+	"say \"hi\"" [label="say \"hi\" (2)"];
+	"a" -> "b";
+	"a" -> "c";
+	"b" -> "c";
+}
+`)
+}
+
+func TestWriteDOTCycle(t *testing.T) {
+	// Presort reports the cycle from Linearize, so
+	// every dependency in it has been added
+	l := implementations.NewPresort()
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "a"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	_, err := l.Linearize()
+	var cycleErr *common.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+	}
+	checkDOT(t, l, common.DOTOptions{Cycle: cycleErr.Cycle}, `digraph dependencies {
+	"a";
+	"b";
+	"c";
+	"a" -> "b" [color=red];
+	"b" -> "c" [color=red];
+	"c" -> "a" [color=red];
+}
+`)
+
+	// PearceKelly rejects the dependency which would close the
+	// cycle, so that one should be dashed
+	l = implementations.NewPearceKelly()
+	deps = []dep{{"a", "b"}, {"b", "c"}, {"a", "d"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	if err := l.AddDependency("c", "a"); !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+	}
+	checkDOT(t, l, common.DOTOptions{Cycle: cycleErr.Cycle}, `digraph dependencies {
+	"a";
+	"b";
+	"c";
+	"d";
+	"a" -> "b" [color=red];
+	"a" -> "d";
+	"b" -> "c" [color=red];
+	"c" -> "a" [color=red, style=dashed];
+}
+`)
+}
+
+func checkDOT(t *testing.T, l common.Linearizer, opts common.DOTOptions, expected string) {
+	buf := &bytes.Buffer{}
+	if err := common.WriteDOT(buf, l.(common.Inspector), opts); err != nil {
+		t.Fatalf("Unexpected error from WriteDOT for %s: %s", l, err.Error())
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("WriteDOT wrote the wrong graph for %s.\n\tExpected:\n%s\n\tGot:\n%s", l, expected, got)
+	}
+}
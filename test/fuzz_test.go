@@ -0,0 +1,156 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"strconv"
+	"testing"
+)
+
+// maxFuzzPhases is the largest number of phases FuzzDifferential
+// will generate. Small graphs are enough to find most bugs, and
+// they keep Unix (which starts a process each time) fast enough.
+const maxFuzzPhases = 16
+
+// fuzzFactories returns a constructor for each implementation which
+// FuzzDifferential can run, a few of them in strict mode, and the ones which
+// implement common.Handler adding dependencies by handle. Unix always
+// runs with the InProcess option, and also runs the tsort command if it
+// is installed.
+func fuzzFactories() []common.Factory {
+	results := []common.Factory{
+		func() common.Linearizer { return implementations.NewGoraph() },
+		func() common.Linearizer { return implementations.NewGraph() },
+		func() common.Linearizer { return implementations.NewMaps() },
+		func() common.Linearizer { return implementations.NewLists() },
+		func() common.Linearizer { return implementations.NewPresort() },
+		func() common.Linearizer { return implementations.NewKahn() },
+		func() common.Linearizer { return implementations.NewPearceKelly() },
+		func() common.Linearizer { return implementations.NewUnix(implementations.InProcess()) },
+		// Strict mode checks for cycles in AddDependency
+		// instead, which is a different code path
+		func() common.Linearizer { return implementations.NewMaps(implementations.Strict()) },
+		func() common.Linearizer { return implementations.NewPresort(implementations.Strict()) },
+		func() common.Linearizer { return implementations.NewKahn(implementations.Strict()) },
+	}
+	// Adding dependencies by handle is a different code path too
+	results = append(results, handleFactories...)
+	if hasTsort {
+		results = append(results, func() common.Linearizer { return implementations.NewUnix() })
+	}
+	return results
+}
+
+// FuzzDifferential builds the same random graph with every implementation
+// and checks that they agree on whether it has a cycle, and that every order
+// they return satisfies all of the dependencies. The first byte of data is the
+// number of phases, and each pair of bytes after that is a dependency.
+func FuzzDifferential(f *testing.F) {
+	// The test cases and the cycle from correctness_test.go
+	f.Add([]byte{5, 0, 4, 2, 3, 0, 1, 1, 2, 1, 3, 3, 4})
+	f.Add([]byte{4, 0, 1, 1, 2, 2, 3, 0, 3})
+	f.Add([]byte{5, 0, 1, 1, 2, 2, 0, 1, 3, 3, 4})
+	// Dependencies which are added in the reverse order, which make
+	// Presort move phases around
+	f.Add([]byte{6, 4, 5, 3, 4, 2, 3, 1, 2, 0, 1})
+	f.Add([]byte{6, 5, 0, 4, 0, 3, 1, 2, 5, 1, 2})
+	// A cycle which is only closed by the last dependency
+	f.Add([]byte{4, 3, 2, 2, 1, 1, 0, 0, 3})
+	// Duplicate dependencies and phases without any
+	f.Add([]byte{7, 1, 2, 1, 2, 5, 3})
+
+	factories := fuzzFactories()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		phases, deps := decodeFuzzGraph(data)
+		edges := map[string][]string{}
+		for _, d := range deps {
+			edges[d.Before] = append(edges[d.Before], d.After)
+		}
+		// NewCycleError doesn't depend on any implementation,
+		// so it tells us what the right answer is.
+		expectCycle := common.NewCycleError(phases, edges) != nil
+
+		for _, newLinearizer := range factories {
+			l := newLinearizer()
+			got, err := buildAndLinearize(l, phases, deps)
+			if expectCycle {
+				var cycleErr *common.CycleError
+				if !errors.As(err, &cycleErr) {
+					t.Fatalf("Expected a *common.CycleError from %s for phases %v and dependencies %v but got: %v",
+						l, phases, deps, err)
+				}
+				checkFuzzCycle(t, l, cycleErr.Cycle, edges)
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s failed for phases %v and dependencies %v\nGot error: %s", l, phases, deps, err.Error())
+			}
+			if err := common.Validate(phases, deps, got); err != nil {
+				t.Fatalf("%s returned an invalid order for phases %v and dependencies %v\n\tGot: %v\n\tError: %s",
+					l, phases, deps, got, err.Error())
+			}
+		}
+		checkTyped(t, phases, deps, expectCycle)
+	})
+}
+
+// decodeFuzzGraph turns data into a list of phases and dependencies.
+// Self-dependencies are skipped since every implementation rejects them.
+func decodeFuzzGraph(data []byte) ([]string, []common.Dependency) {
+	if len(data) == 0 {
+		return []string{}, []common.Dependency{}
+	}
+	numPhases := int(data[0])%maxFuzzPhases + 1
+	phases := make([]string, numPhases)
+	for i := range phases {
+		phases[i] = strconv.Itoa(i)
+	}
+	deps := []common.Dependency{}
+	for i := 1; i+1 < len(data); i += 2 {
+		before, after := int(data[i])%numPhases, int(data[i+1])%numPhases
+		if before == after {
+			continue
+		}
+		deps = append(deps, common.Dependency{Before: phases[before], After: phases[after]})
+	}
+	return phases, deps
+}
+
+// buildAndLinearize adds phases and deps to l and linearizes it. A cycle
+// may be reported by AddDependency or by Linearize, so either error is
+// returned.
+func buildAndLinearize(l common.Linearizer, phases []string, deps []common.Dependency) ([]string, error) {
+	for _, id := range phases {
+		if err := l.AddPhase(id); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range deps {
+		if err := l.AddDependency(d.Before, d.After); err != nil {
+			return nil, err
+		}
+	}
+	return l.Linearize()
+}
+
+// checkFuzzCycle checks that every phase in cycle must run
+// before the next one, and that the last closes the loop.
+func checkFuzzCycle(t *testing.T, l common.Linearizer, cycle []string, edges map[string][]string) {
+	if len(cycle) < 2 {
+		t.Fatalf("Cycle for %s was too short: %v", l, cycle)
+	}
+	for i, id := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		found := false
+		for _, dependent := range edges[id] {
+			if dependent == next {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("Cycle for %s contained %s -> %s which is not a dependency.\n\tGot: %v", l, id, next, cycle)
+		}
+	}
+}
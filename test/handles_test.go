@@ -0,0 +1,190 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"github.com/albrow/dependency-linearization/lineartest"
+	"reflect"
+	"testing"
+)
+
+// byHandle wraps a Linearizer which implements common.Handler, and
+// adds every phase and dependency by handle, so that the handle API
+// can be checked with the same tests as everything else.
+type byHandle struct {
+	common.Linearizer
+	handles map[string]common.PhaseID
+}
+
+func newByHandle(l common.Linearizer) common.Linearizer {
+	return &byHandle{Linearizer: l, handles: map[string]common.PhaseID{}}
+}
+
+func (h *byHandle) AddPhase(id string) error {
+	handle, err := h.Linearizer.(common.Handler).AddPhaseID(id)
+	if err != nil {
+		return err
+	}
+	h.handles[id] = handle
+	return nil
+}
+
+func (h *byHandle) AddDependency(a, b string) error {
+	aHandle, found := h.handles[a]
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, a)
+	}
+	bHandle, found := h.handles[b]
+	if !found {
+		return fmt.Errorf("%w with id = %s", common.ErrUnknownPhase, b)
+	}
+	return h.Linearizer.(common.Handler).AddDependencyByID(aHandle, bHandle)
+}
+
+func (h *byHandle) Reset() {
+	h.Linearizer.Reset()
+	h.handles = map[string]common.PhaseID{}
+}
+
+func (h *byHandle) String() string {
+	return fmt.Sprintf("%s (by handle)", h.Linearizer)
+}
+
+// handleFactories holds a constructor for each implementation
+// which implements common.Handler, wrapped with newByHandle
+var handleFactories = []common.Factory{
+	func() common.Linearizer { return newByHandle(implementations.NewPresort()) },
+	func() common.Linearizer { return newByHandle(implementations.NewKahn()) },
+	func() common.Linearizer { return newByHandle(implementations.NewPresort(implementations.Strict())) },
+	func() common.Linearizer { return newByHandle(implementations.NewKahn(implementations.Strict())) },
+}
+
+func TestHandlesConformance(t *testing.T) {
+	for _, newLinearizer := range handleFactories {
+		t.Run(fmt.Sprint(newLinearizer()), func(t *testing.T) {
+			lineartest.RunConformance(t, newLinearizer)
+		})
+	}
+}
+
+// TestHandlesMatchIds checks that adding dependencies by handle
+// gives exactly the same order as adding them by id
+func TestHandlesMatchIds(t *testing.T) {
+	reversed := []dep{}
+	for i := len(linear10Deps) - 1; i >= 0; i-- {
+		reversed = append(reversed, linear10Deps[i])
+	}
+	cases := [][]dep{
+		{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}},
+		{{"d", "c"}, {"c", "b"}, {"b", "a"}},
+		{{"a", "b"}, {"c", ""}, {"d", "a"}, {"b", "e"}, {"e", "c"}},
+		{{"e", "a"}, {"d", "b"}, {"c", "e"}, {"b", "c"}},
+		linear10Deps,
+		tree10Deps,
+		reversed,
+	}
+	pairs := []struct {
+		byId     common.Factory
+		byHandle common.Factory
+	}{
+		{func() common.Linearizer { return implementations.NewPresort() }, handleFactories[0]},
+		{func() common.Linearizer { return implementations.NewKahn() }, handleFactories[1]},
+	}
+	for _, pair := range pairs {
+		for _, deps := range cases {
+			l, h := pair.byId(), pair.byHandle()
+			expected, err := linearizeCase(l, deps)
+			if err != nil {
+				t.Fatalf("%s failed for test case: %v\nGot error: %s", l, deps, err.Error())
+			}
+			got, err := linearizeCase(h, deps)
+			if err != nil {
+				t.Fatalf("%s failed for test case: %v\nGot error: %s", h, deps, err.Error())
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("%s returned a different order than %s for %v.\n\tExpected: %v\n\tGot: %v", h, l, deps, expected, got)
+			}
+		}
+	}
+}
+
+func TestPresortHandles(t *testing.T) {
+	testHandles(t, implementations.NewPresort())
+}
+
+func TestKahnHandles(t *testing.T) {
+	testHandles(t, implementations.NewKahn())
+}
+
+func testHandles(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	h, ok := l.(common.Handler)
+	if !ok {
+		t.Fatalf("%s does not implement common.Handler", l)
+	}
+	a, err := h.AddPhaseID("a")
+	if err != nil {
+		t.Fatalf("%s failed during AddPhaseID: %s", l, err.Error())
+	}
+	// Handles and ids can be mixed
+	if err := l.AddPhase("b"); err != nil {
+		t.Fatalf("%s failed during AddPhase: %s", l, err.Error())
+	}
+	c, err := h.AddPhaseID("c")
+	if err != nil {
+		t.Fatalf("%s failed during AddPhaseID: %s", l, err.Error())
+	}
+	if a == c {
+		t.Errorf("%s returned the same handle for a and c: %d", l, a)
+	}
+	if _, err := h.AddPhaseID("a"); !errors.Is(err, common.ErrDuplicatePhase) {
+		t.Errorf("Expected common.ErrDuplicatePhase from %s but got: %v", l, err)
+	}
+	if err := h.AddDependencyByID(c, a); err != nil {
+		t.Fatalf("%s failed during AddDependencyByID: %s", l, err.Error())
+	}
+	if err := l.AddDependency("b", "c"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+	}
+	compareResults(t, l, got, []string{"b", "c", "a"})
+
+	for _, handle := range []common.PhaseID{-1, c + 100} {
+		if err := h.AddDependencyByID(a, handle); !errors.Is(err, common.ErrUnknownPhase) {
+			t.Errorf("Expected common.ErrUnknownPhase from %s for handle %d but got: %v", l, handle, err)
+		}
+	}
+	if err := h.AddDependencyByID(a, a); !errors.Is(err, common.ErrSelfDependency) {
+		t.Errorf("Expected common.ErrSelfDependency from %s but got: %v", l, err)
+	}
+
+	// Removed phases lose their handles, but the others keep theirs
+	if m, ok := l.(common.Mutable); ok {
+		if err := m.RemovePhase("c"); err != nil {
+			t.Fatalf("%s failed during RemovePhase: %s", l, err.Error())
+		}
+		if err := h.AddDependencyByID(c, a); !errors.Is(err, common.ErrUnknownPhase) {
+			t.Errorf("Expected common.ErrUnknownPhase from %s for a removed phase but got: %v", l, err)
+		}
+		d, err := h.AddPhaseID("d")
+		if err != nil {
+			t.Fatalf("%s failed during AddPhaseID: %s", l, err.Error())
+		}
+		if d == c {
+			t.Errorf("%s reused the handle of a removed phase: %d", l, d)
+		}
+		if err := h.AddDependencyByID(a, d); err != nil {
+			t.Fatalf("%s failed during AddDependencyByID: %s", l, err.Error())
+		}
+		got, err := l.Linearize()
+		if err != nil {
+			t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+		}
+		compareResults(t, l, got, []string{"b", "a", "d"})
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"sort"
+	"testing"
+)
+
+func TestMapsInspector(t *testing.T) {
+	testInspector(t, implementations.NewMaps())
+}
+
+func TestListsInspector(t *testing.T) {
+	testInspector(t, implementations.NewLists())
+}
+
+func TestPresortInspector(t *testing.T) {
+	testInspector(t, implementations.NewPresort())
+}
+
+func TestKahnInspector(t *testing.T) {
+	testInspector(t, implementations.NewKahn())
+}
+
+func TestPearceKellyInspector(t *testing.T) {
+	testInspector(t, implementations.NewPearceKelly())
+}
+
+func testInspector(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	in, ok := l.(common.Inspector)
+	if !ok {
+		t.Fatalf("%s does not implement common.Inspector", l)
+	}
+	// This case has a bit of everything. b -> d is added a second
+	// time, which should not count as a separate dependency.
+	deps := []dep{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}, {"b", "d"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	checkInspector(t, l, in)
+	// Linearizing should not change anything
+	if _, err := l.Linearize(); err != nil {
+		t.Fatalf("%s failed during linearize for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	checkInspector(t, l, in)
+}
+
+// checkInspector checks what in has to say about the
+// phases and dependencies in testInspector
+func checkInspector(t *testing.T, l common.Linearizer, in common.Inspector) {
+	if !in.HasPhase("a") {
+		t.Errorf("Expected %s to have phase a", l)
+	}
+	if in.HasPhase("z") {
+		t.Errorf("Expected %s not to have phase z", l)
+	}
+	if got := in.NumPhases(); got != 5 {
+		t.Errorf("Expected %s to have 5 phases but got %d", l, got)
+	}
+	if got := in.NumDependencies(); got != 6 {
+		t.Errorf("Expected %s to have 6 dependencies but got %d", l, got)
+	}
+	compareSets(t, l, in.Phases(), []string{"a", "b", "c", "d", "e"})
+	compareSets(t, l, in.DependenciesOf("d"), []string{"b", "c"})
+	compareSets(t, l, in.DependenciesOf("a"), []string{})
+	compareSets(t, l, in.DependentsOf("b"), []string{"c", "d"})
+	compareSets(t, l, in.DependentsOf("e"), []string{})
+	if got := in.DependenciesOf("z"); got != nil {
+		t.Errorf("Expected %s to return nil for DependenciesOf an unknown phase but got %v", l, got)
+	}
+	if got := in.DependentsOf("z"); got != nil {
+		t.Errorf("Expected %s to return nil for DependentsOf an unknown phase but got %v", l, got)
+	}
+}
+
+// compareSets checks that got and expected have the same ids, in any order
+func compareSets(t *testing.T, l common.Linearizer, got []string, expected []string) {
+	sortedGot := append([]string{}, got...)
+	sort.Strings(sortedGot)
+	sortedExpected := append([]string{}, expected...)
+	sort.Strings(sortedExpected)
+	compareResults(t, l, sortedGot, sortedExpected)
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+// TestLinearizeInto checks that LinearizeInto returns the same results as
+// Linearize, and that it writes them into dst when dst is big enough
+func TestLinearizeInto(t *testing.T) {
+	for _, newLinearizer := range factories {
+		l := newLinearizer()
+		into, ok := l.(common.IntoLinearizer)
+		if !ok {
+			t.Fatalf("%s does not implement common.IntoLinearizer", l)
+		}
+		if err := prepareCase(l, tree10Deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, tree10Deps, err.Error())
+		}
+		expected, err := l.Linearize()
+		if err != nil {
+			t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+		}
+		got, err := into.LinearizeInto(nil)
+		if err != nil {
+			t.Fatalf("%s failed during LinearizeInto: %s", l, err.Error())
+		}
+		compareResults(t, l, got, expected)
+
+		// Anything already in dst is replaced
+		dst := make([]string, 3, 20)
+		got, err = into.LinearizeInto(dst)
+		if err != nil {
+			t.Fatalf("%s failed during LinearizeInto: %s", l, err.Error())
+		}
+		compareResults(t, l, got, expected)
+		if &got[0] != &dst[0] {
+			t.Errorf("%s did not reuse dst even though it had enough capacity", l)
+		}
+
+		// Cycles are reported the same way
+		if err := l.AddDependency("1", "0"); err != nil {
+			// Strict implementations report the cycle here instead
+			if errors.As(err, new(*common.CycleError)) {
+				continue
+			}
+			t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+		}
+		if _, err := into.LinearizeInto(dst); !errors.As(err, new(*common.CycleError)) {
+			t.Errorf("Expected a *common.CycleError from %s but got: %v", l, err)
+		}
+	}
+}
+
+// TestResetReusesMemory checks that once an implementation has built a
+// graph, building it again after Reset doesn't allocate. Presort and Lists
+// keep everything except the elements of their container/list lists, which
+// can't be reused. Presort allocates one for each phase. Lists has one for
+// each phase and each dependency, and also has to box the value it puts in
+// each one. Graph, Goraph and Unix rely on other packages or the tsort
+// command, which start over every time, so they aren't checked.
+func TestResetReusesMemory(t *testing.T) {
+	p := prepareCase(implementations.NewKahn(), tree10Deps)
+	numPhases, numDeps := float64(len(p.phaseFuncs)), float64(len(p.depFuncs))
+	cases := []struct {
+		l      common.Linearizer
+		allocs float64
+	}{
+		{implementations.NewKahn(), 0},
+		{implementations.NewPearceKelly(), 0},
+		{implementations.NewMaps(), 0},
+		{implementations.NewPresort(), numPhases},
+		{implementations.NewLists(), 2 * (numPhases + numDeps)},
+	}
+	for _, c := range cases {
+		l := c.l
+		into := l.(common.IntoLinearizer)
+		funcs := prepareCase(l, tree10Deps).getFuncs()
+		dst := []string{}
+		run := func() {
+			for _, f := range funcs {
+				if err := f(); err != nil {
+					t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, tree10Deps, err.Error())
+				}
+			}
+			var err error
+			dst, err = into.LinearizeInto(dst)
+			if err != nil {
+				t.Fatalf("%s failed during LinearizeInto: %s", l, err.Error())
+			}
+			l.Reset()
+		}
+		// The first run allocates everything which will be reused
+		run()
+		if allocs := testing.AllocsPerRun(10, run); allocs != c.allocs {
+			t.Errorf("Expected %s to make %v allocations after Reset, but it made %v", l, c.allocs, allocs)
+		}
+
+		// Nothing from the previous graph should be left over
+		if err := prepareCase(l, linear10Deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, linear10Deps, err.Error())
+		}
+		got, err := l.Linearize()
+		if err != nil {
+			t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+		}
+		compareResults(t, l, got, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"})
+		l.Reset()
+	}
+}
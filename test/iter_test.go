@@ -0,0 +1,117 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+func TestPresortIter(t *testing.T) {
+	testIter(t, func() common.Linearizer { return implementations.NewPresort() })
+}
+
+func TestKahnIter(t *testing.T) {
+	testIter(t, func() common.Linearizer { return implementations.NewKahn() })
+	testIter(t, func() common.Linearizer {
+		return implementations.NewKahn(implementations.WithTieBreak(common.Lexicographic))
+	})
+}
+
+// testIter checks that LinearizeIter yields the same order as Linearize,
+// that it stops when the loop breaks, that linearizing in the middle of the
+// loop doesn't change what it yields, and that it ends with the same error
+// as Linearize when there is a cycle
+func testIter(t *testing.T, newLinearizer common.Factory) {
+	cases := [][]dep{
+		{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}},
+		{{"d", "c"}, {"c", "b"}, {"b", "a"}},
+		{{"a", "b"}, {"c", ""}, {"d", "a"}, {"b", "e"}, {"e", "c"}},
+		linear10Deps,
+		tree10Deps,
+	}
+	for _, deps := range cases {
+		l := newLinearizer()
+		it, ok := l.(common.Iterator)
+		if !ok {
+			t.Fatalf("%s does not implement common.Iterator", l)
+		}
+		expected, err := linearizeCase(l, deps)
+		if err != nil {
+			t.Fatalf("%s failed for test case: %v\nGot error: %s", l, deps, err.Error())
+		}
+		got := []string{}
+		for id, err := range it.LinearizeIter() {
+			if err != nil {
+				t.Fatalf("%s failed during LinearizeIter: %s", l, err.Error())
+			}
+			got = append(got, id)
+			// The iterator has its own state, so this shouldn't affect it
+			if _, err := l.Linearize(); err != nil {
+				t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+			}
+		}
+		compareResults(t, l, got, expected)
+
+		// Breaking out of the loop stops the iteration
+		got = []string{}
+		for id := range it.LinearizeIter() {
+			got = append(got, id)
+			if len(got) == 2 {
+				break
+			}
+		}
+		compareResults(t, l, got, expected[:2])
+	}
+
+	l := newLinearizer()
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "b"}, {"d", ""}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation: %s", l, err.Error())
+	}
+	_, expectedErr := l.Linearize()
+	var ids []string
+	var err error
+	for id, idErr := range l.(common.Iterator).LinearizeIter() {
+		if err != nil {
+			t.Fatalf("%s kept iterating after an error. Got: %q, %v", l, id, idErr)
+		}
+		if idErr != nil {
+			if id != "" {
+				t.Errorf("Expected an empty id with the error from %s but got: %q", l, id)
+			}
+			err = idErr
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if !errors.As(err, new(*common.CycleError)) {
+		t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+	}
+	if expectedErr == nil || err.Error() != expectedErr.Error() {
+		t.Errorf("LinearizeIter returned a different error than Linearize for %s.\n\tExpected: %v\n\tGot: %v", l, expectedErr, err)
+	}
+	for _, id := range ids {
+		if id != "a" && id != "d" {
+			t.Errorf("%s yielded %s, which is part of the cycle: %v", l, id, ids)
+		}
+	}
+}
+
+// TestKahnIterStreams checks that Kahn yields the phases which are ready
+// before it finds out that the rest of them are in a cycle
+func TestKahnIterStreams(t *testing.T) {
+	l := implementations.NewKahn()
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "b"}, {"d", ""}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation: %s", l, err.Error())
+	}
+	got := []string{}
+	for id, err := range l.(common.Iterator).LinearizeIter() {
+		if err != nil {
+			break
+		}
+		got = append(got, id)
+	}
+	compareResults(t, l, got, []string{"a", "d"})
+}
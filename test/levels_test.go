@@ -0,0 +1,74 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+func TestMapsLevels(t *testing.T) {
+	testLevels(t, implementations.NewMaps())
+}
+
+func TestListsLevels(t *testing.T) {
+	testLevels(t, implementations.NewLists())
+}
+
+func TestKahnLevels(t *testing.T) {
+	testLevels(t, implementations.NewKahn())
+}
+
+func testLevels(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	leveler, ok := l.(common.Leveler)
+	if !ok {
+		t.Fatalf("%s does not implement common.Leveler", l)
+	}
+	// A diamond, a -> b -> d and a -> c -> d, plus e which
+	// does not depend on anything.
+	deps := []dep{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"e", ""}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	got, err := leveler.LinearizeLevels()
+	if err != nil {
+		t.Fatalf("%s failed during LinearizeLevels for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	expected := [][]string{{"a", "e"}, {"b", "c"}, {"d"}}
+	compareLevels(t, l, got, expected)
+
+	// Linearize should agree with the levels. This also checks that
+	// LinearizeLevels left the dependency graph intact.
+	flat, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	if len(flat) != 5 {
+		t.Fatalf("Results were not the correct length for %s. Expected 5 phases but got %d.\n\tGot: %v", l, len(flat), flat)
+	}
+	compareLevels(t, l, [][]string{flat[0:2], flat[2:4], flat[4:5]}, expected)
+
+	// Close the loop d -> a and make sure we get a CycleError
+	if err := l.AddDependency("d", "a"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	if _, err := leveler.LinearizeLevels(); err == nil {
+		t.Errorf("Expected error for cyclical graph but got none for %s", l)
+	} else if !errors.As(err, new(*common.CycleError)) {
+		t.Errorf("Expected a *common.CycleError for %s but got %T: %s", l, err, err.Error())
+	}
+}
+
+// compareLevels checks that got has the same levels as expected. Phases
+// within a level can be in any order.
+func compareLevels(t *testing.T, l common.Linearizer, got [][]string, expected [][]string) {
+	if len(got) != len(expected) {
+		t.Errorf("Wrong number of levels for %s. Expected %d but got %d.\n\tExpected: %v\n\tGot: %v",
+			l, len(expected), len(got), expected, got)
+		return
+	}
+	for i := range expected {
+		compareSets(t, l, got[i], expected[i])
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+func TestMapsMutable(t *testing.T) {
+	testMutable(t, implementations.NewMaps())
+}
+
+func TestListsMutable(t *testing.T) {
+	testMutable(t, implementations.NewLists())
+}
+
+func TestPresortMutable(t *testing.T) {
+	testMutable(t, implementations.NewPresort())
+}
+
+func TestGraphMutable(t *testing.T) {
+	testMutable(t, implementations.NewGraph())
+}
+
+func testMutable(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	m, ok := l.(common.Mutable)
+	if !ok {
+		t.Fatalf("%s does not implement common.Mutable", l)
+	}
+
+	// Removing b from a -> b -> c -> d should leave only c -> d
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	if err := m.RemovePhase("b"); err != nil {
+		t.Fatalf("%s failed during RemovePhase: %s", l, err.Error())
+	}
+	checkValidOrder(t, l, []string{"a", "c", "d"}, []dep{{"c", "d"}})
+	// The removed phase can be added again
+	if err := l.AddPhase("b"); err != nil {
+		t.Fatalf("%s failed to add a phase again after removing it: %s", l, err.Error())
+	}
+	if err := l.AddDependency("d", "b"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	checkValidOrder(t, l, []string{"a", "b", "c", "d"}, []dep{{"c", "d"}, {"d", "b"}})
+	l.Reset()
+
+	// Removing the dependency c -> a from a -> b -> c -> a should break the cycle
+	deps = []dep{{"a", "b"}, {"b", "c"}, {"c", "a"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	if err := m.RemoveDependency("c", "a"); err != nil {
+		t.Fatalf("%s failed during RemoveDependency: %s", l, err.Error())
+	}
+	checkValidOrder(t, l, []string{"a", "b", "c"}, []dep{{"a", "b"}, {"b", "c"}})
+
+	// Removing things which don't exist should return an error
+	if err := m.RemovePhase("z"); !errors.Is(err, common.ErrUnknownPhase) {
+		t.Errorf("Expected ErrUnknownPhase from %s when removing a phase which does not exist but got: %v", l, err)
+	}
+	if err := m.RemoveDependency("z", "a"); !errors.Is(err, common.ErrUnknownPhase) {
+		t.Errorf("Expected ErrUnknownPhase from %s when removing a dependency on a phase which does not exist but got: %v", l, err)
+	}
+	if err := m.RemoveDependency("c", "a"); !errors.Is(err, common.ErrUnknownDependency) {
+		t.Errorf("Expected ErrUnknownDependency from %s when removing a dependency which does not exist but got: %v", l, err)
+	}
+}
+
+// checkValidOrder linearizes l and checks that the results contain
+// exactly the given phases and satisfy every dependency in deps.
+func checkValidOrder(t *testing.T, l common.Linearizer, phases []string, deps []dep) {
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize: %s", l, err.Error())
+	}
+	dependencies := []common.Dependency{}
+	for _, d := range deps {
+		dependencies = append(dependencies, common.Dependency{Before: d.first, After: d.second})
+	}
+	if err := common.Validate(phases, dependencies, got); err != nil {
+		t.Errorf("%s returned an invalid order.\n\tGot: %v\n\tError: %s", l, got, err.Error())
+	}
+}
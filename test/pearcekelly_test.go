@@ -0,0 +1,37 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+// TestPearceKellyRejectsCycle checks that AddDependency reports a cycle at
+// the exact dependency which closes the loop, and that the dependency is
+// not added.
+func TestPearceKellyRejectsCycle(t *testing.T) {
+	l := implementations.NewPearceKelly()
+	// Adding the dependencies backwards makes every one of them reorder the phases
+	deps := []dep{{"c", "d"}, {"b", "c"}, {"a", "b"}, {"e", ""}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	err := l.AddDependency("d", "a")
+	var cycleErr *common.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError from AddDependency(\"d\", \"a\") but got: %v", err)
+	}
+	compareResults(t, l, cycleErr.Cycle, []string{"d", "a", "b", "c"})
+	if len(cycleErr.Components) != 1 || len(cycleErr.Components[0]) != 4 {
+		t.Errorf("Expected one cyclic component with 4 phases but got: %v", cycleErr.Components)
+	}
+
+	// The rejected dependency should not have been added, so everything
+	// else still works as before.
+	checkValidOrder(t, l, []string{"a", "b", "c", "d", "e"}, []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}})
+	if err := l.AddDependency("e", "a"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	checkValidOrder(t, l, []string{"a", "b", "c", "d", "e"}, []dep{{"e", "a"}, {"a", "b"}, {"b", "c"}, {"c", "d"}})
+}
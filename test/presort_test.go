@@ -0,0 +1,55 @@
+package test
+
+import (
+	"fmt"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+// TestPresortMovesBetween checks that Presort keeps working when it moves
+// so many phases into the same spot that it runs out of room between the
+// positions of its neighbors, and has to renumber the whole list.
+func TestPresortMovesBetween(t *testing.T) {
+	l := implementations.NewPresort()
+	phases := []string{"z", "a", "b"}
+	deps := []dep{{"a", "b"}}
+	for _, id := range phases {
+		if err := l.AddPhase(id); err != nil {
+			t.Fatalf("%s failed during AddPhase: %s", l, err.Error())
+		}
+	}
+	if err := l.AddDependency("a", "b"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	// b depends on a, so a can't move after each new phase. Instead, each
+	// new phase has to move directly before a, i.e. between a and the phase
+	// which moved there last.
+	for i := 0; i < 40; i++ {
+		id := fmt.Sprint(i)
+		phases = append(phases, id)
+		deps = append(deps, dep{id, "a"})
+		if err := l.AddPhase(id); err != nil {
+			t.Fatalf("%s failed during AddPhase: %s", l, err.Error())
+		}
+		if err := l.AddDependency(id, "a"); err != nil {
+			t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+		}
+		checkValidOrder(t, l, phases, deps)
+	}
+	// Dependencies which are already satisfied after
+	// renumbering shouldn't move anything
+	expected, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+	}
+	for _, d := range []dep{{"z", "b"}, {"0", "39"}, {"39", "b"}} {
+		if err := l.AddDependency(d.first, d.second); err != nil {
+			t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+		}
+	}
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+	}
+	compareResults(t, l, got, expected)
+}
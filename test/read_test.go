@@ -0,0 +1,258 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// readers holds the reader for each fixture file extension
+var readers = map[string]func(r io.Reader, l common.Linearizer) error{
+	".tsort": common.ReadTsort,
+	".json":  common.ReadJSON,
+	".dot":   common.ReadDOT,
+}
+
+// TestFixtures reads each file in testdata into every implementation. Files
+// named cycle.* should be reported as a cycle, and everything else should be
+// linearized in a valid order.
+func TestFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		read := readers[filepath.Ext(file)]
+		if read == nil {
+			continue
+		}
+		phases, deps := readFixture(t, file, read)
+		for _, newLinearizer := range factories {
+			l := newLinearizer()
+			t.Run(fmt.Sprintf("%s/%s", filepath.Base(file), l), func(t *testing.T) {
+				err := readFile(file, read, l)
+				var order []string
+				if err == nil {
+					order, err = l.Linearize()
+				}
+				if strings.HasPrefix(filepath.Base(file), "cycle.") {
+					var cycleErr *common.CycleError
+					if !errors.As(err, &cycleErr) {
+						t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+				}
+				if err := common.Validate(phases, deps, order); err != nil {
+					t.Errorf("%s returned an invalid order: %s", l, err.Error())
+				}
+			})
+		}
+	}
+}
+
+// TestFixturesMatch checks that each fixture describes
+// the same graph in every format
+func TestFixturesMatch(t *testing.T) {
+	for _, name := range []string{"build", "cycle"} {
+		var expected string
+		for _, ext := range []string{".tsort", ".json", ".dot"} {
+			file := filepath.Join("testdata", name+ext)
+			phases, deps := readFixture(t, file, readers[ext])
+			sort.Strings(phases)
+			sort.Slice(deps, func(i, j int) bool {
+				return deps[i].Before+" "+deps[i].After < deps[j].Before+" "+deps[j].After
+			})
+			got := fmt.Sprint(phases, deps)
+			if expected == "" {
+				expected = got
+			} else if got != expected {
+				t.Errorf("%s describes a different graph.\n\tExpected: %s\n\tGot: %s", file, expected, got)
+			}
+		}
+	}
+}
+
+// readFixture reads file into a Kahn implementation, which keeps cycles
+// until Linearize, and returns the phases and dependencies it holds
+func readFixture(t *testing.T, file string, read func(io.Reader, common.Linearizer) error) ([]string, []common.Dependency) {
+	l := implementations.NewKahn()
+	if err := readFile(file, read, l); err != nil {
+		t.Fatalf("Could not read %s: %s", file, err.Error())
+	}
+	in := l.(common.Inspector)
+	phases := in.Phases()
+	deps := []common.Dependency{}
+	for _, id := range phases {
+		for _, after := range in.DependentsOf(id) {
+			deps = append(deps, common.Dependency{Before: id, After: after})
+		}
+	}
+	return phases, deps
+}
+
+func readFile(file string, read func(io.Reader, common.Linearizer) error, l common.Linearizer) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return read(f, l)
+}
+
+func TestReadOrder(t *testing.T) {
+	inputs := map[string]string{
+		".tsort": "b c\na b\nd d\n",
+		".json":  `{"b": ["c"], "a": ["b"], "d": []}`,
+		".dot":   "digraph { b -> c; a -> b; d }",
+	}
+	for format, input := range inputs {
+		l := implementations.NewKahn()
+		if err := readers[format](strings.NewReader(input), l); err != nil {
+			t.Fatalf("Unexpected error reading %s: %s", format, err.Error())
+		}
+		in := l.(common.Inspector)
+		// Phases are added in the order they first appear
+		if got, expected := in.Phases(), []string{"b", "c", "a", "d"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("Wrong phases after reading %s.\n\tExpected: %v\n\tGot: %v", format, expected, got)
+		}
+		if got, expected := in.DependentsOf("a"), []string{"b"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("Wrong dependents of a after reading %s.\n\tExpected: %v\n\tGot: %v", format, expected, got)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	cases := []struct {
+		read     func(io.Reader, common.Linearizer) error
+		input    string
+		expected string
+	}{
+		{
+			read:     common.ReadTsort,
+			input:    "a b\nb c\n\nd\n",
+			expected: `Could not parse tsort on line 4: "d" is not part of a pair`,
+		},
+		{
+			read:     common.ReadJSON,
+			input:    "{\n\t\"a\": [\"b\"],\n\t\"b\": \"c\"\n}",
+			expected: `Could not parse JSON on line 3: expected a list of the phases which run after "b" but got "c"`,
+		},
+		{
+			read:     common.ReadJSON,
+			input:    "{\n\t\"a\": [1]\n}",
+			expected: `Could not parse JSON on line 2: expected the id of a phase but got 1`,
+		},
+		{
+			read:     common.ReadJSON,
+			input:    "{\n\t\"a\": [\"b\"]\n",
+			expected: `Could not parse JSON on line 2: unexpected end of JSON input`,
+		},
+		{
+			read:     common.ReadDOT,
+			input:    "graph {\n\ta -- b\n}",
+			expected: `Could not parse DOT on line 1: undirected graphs are not supported, use digraph instead`,
+		},
+		{
+			read:     common.ReadDOT,
+			input:    "digraph {\n\ta -> b;\n\tb ->\n}",
+			expected: `Could not parse DOT on line 4: expected a node after -> but got "}"`,
+		},
+		{
+			read:     common.ReadDOT,
+			input:    "digraph {\n\t/* a comment\n\tover two lines */\n\tsubgraph x { a }\n}",
+			expected: `Could not parse DOT on line 4: subgraphs are not supported`,
+		},
+		{
+			read:     common.ReadDOT,
+			input:    "digraph {\n\t\"a\n",
+			expected: `Could not parse DOT on line 2: string is never closed`,
+		},
+	}
+	for _, c := range cases {
+		l := implementations.NewKahn()
+		err := c.read(strings.NewReader(c.input), l)
+		var parseErr *common.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Expected a *common.ParseError for input %q but got: %v", c.input, err)
+			continue
+		}
+		if err.Error() != c.expected {
+			t.Errorf("Wrong error for input %q.\n\tExpected: %s\n\tGot: %s", c.input, c.expected, err.Error())
+		}
+		// Nothing should be added if the input can't be parsed
+		if phases := l.(common.Inspector).Phases(); len(phases) != 0 {
+			t.Errorf("Expected no phases to be added for input %q but got: %v", c.input, phases)
+		}
+	}
+}
+
+// TestReadDependencyErrors checks that errors from AddDependency include the
+// line of the dependency, and that the underlying error can still be found
+func TestReadDependencyErrors(t *testing.T) {
+	l := implementations.NewPearceKelly()
+	err := common.ReadTsort(strings.NewReader("a b\nb c\nc a\n"), l)
+	var cycleErr *common.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+	}
+	expected := "Could not add dependency c -> a on line 3: Detected cycle: c -> a -> b -> c"
+	if err.Error() != expected {
+		t.Errorf("Wrong error from %s.\n\tExpected: %s\n\tGot: %s", l, expected, err.Error())
+	}
+
+	l = implementations.NewPearceKelly()
+	err = common.ReadDOT(strings.NewReader("digraph {\n\ta -> a\n}"), l)
+	if !errors.Is(err, common.ErrSelfDependency) {
+		t.Errorf("Expected common.ErrSelfDependency from %s but got: %v", l, err)
+	}
+}
+
+// TestReadDOTRoundTrip checks that ReadDOT can read
+// everything WriteDOT writes, including quoted ids
+func TestReadDOTRoundTrip(t *testing.T) {
+	original := implementations.NewKahn()
+	deps := []dep{{"a", "b"}, {"b", "c"}, {"a", "c"}, {"say \"hi\"", "c"}, {"back\\slash", ""}, {"two\nlines", "a"}}
+	if err := prepareCase(original, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", original, deps, err.Error())
+	}
+	order, err := original.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize: %s", original, err.Error())
+	}
+	buf := &bytes.Buffer{}
+	if err := common.WriteDOT(buf, original.(common.Inspector), common.DOTOptions{Order: order}); err != nil {
+		t.Fatalf("Unexpected error from WriteDOT: %s", err.Error())
+	}
+	copied := implementations.NewKahn()
+	if err := common.ReadDOT(bytes.NewReader(buf.Bytes()), copied); err != nil {
+		t.Fatalf("Could not read the output of WriteDOT: %s\n%s", err.Error(), buf)
+	}
+	originalIn, copiedIn := original.(common.Inspector), copied.(common.Inspector)
+	expectedPhases := originalIn.Phases()
+	sort.Strings(expectedPhases)
+	gotPhases := copiedIn.Phases()
+	sort.Strings(gotPhases)
+	if !reflect.DeepEqual(gotPhases, expectedPhases) {
+		t.Fatalf("Wrong phases after reading the output of WriteDOT.\n\tExpected: %q\n\tGot: %q", expectedPhases, gotPhases)
+	}
+	for _, id := range expectedPhases {
+		expected, got := originalIn.DependentsOf(id), copiedIn.DependentsOf(id)
+		sort.Strings(expected)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Wrong dependents of %q after reading the output of WriteDOT.\n\tExpected: %q\n\tGot: %q", id, expected, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"github.com/albrow/dependency-linearization/lineartest"
+	"testing"
+)
+
+// strictFactories holds a constructor for each
+// implementation in strict mode
+var strictFactories = []common.Factory{
+	func() common.Linearizer { return implementations.NewGoraph(implementations.Strict()) },
+	func() common.Linearizer { return implementations.NewUnix(unixOptions(implementations.Strict())...) },
+	func() common.Linearizer { return implementations.NewGraph(implementations.Strict()) },
+	func() common.Linearizer { return implementations.NewMaps(implementations.Strict()) },
+	func() common.Linearizer { return implementations.NewLists(implementations.Strict()) },
+	func() common.Linearizer { return implementations.NewPresort(implementations.Strict()) },
+	func() common.Linearizer { return implementations.NewKahn(implementations.Strict()) },
+	// Strict makes no difference to PearceKelly
+	func() common.Linearizer { return implementations.NewPearceKelly(implementations.Strict()) },
+}
+
+func TestStrictConformance(t *testing.T) {
+	for _, newLinearizer := range strictFactories {
+		t.Run(fmt.Sprint(newLinearizer()), func(t *testing.T) {
+			lineartest.RunConformance(t, newLinearizer)
+		})
+	}
+}
+
+// TestStrict checks that in strict mode, AddDependency reports a cycle at the
+// exact dependency which closes the loop, and that the dependency is not added.
+func TestStrict(t *testing.T) {
+	for _, newLinearizer := range strictFactories {
+		l := newLinearizer()
+		deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "c"}, {"e", ""}}
+		if err := prepareCase(l, deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+		}
+		err := l.AddDependency("d", "b")
+		var cycleErr *common.CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("Expected a *common.CycleError from %s for AddDependency(\"d\", \"b\") but got: %v", l, err)
+		}
+		// The cycle starts with the new dependency and then
+		// follows the existing path back to d
+		compareResults(t, l, cycleErr.Cycle, []string{"d", "b", "c"})
+		if len(cycleErr.Components) != 1 || len(cycleErr.Components[0]) != 3 {
+			t.Errorf("Expected one cyclic component with 3 phases for %s but got: %v", l, cycleErr.Components)
+		}
+		checkValidOrder(t, l, []string{"a", "b", "c", "d", "e"}, []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "c"}})
+	}
+}
+
+// TestStrictAfterRemoving checks that strict mode forgets about removed
+// phases and dependencies, so they no longer count towards a cycle.
+func TestStrictAfterRemoving(t *testing.T) {
+	for _, newLinearizer := range strictFactories {
+		l := newLinearizer()
+		m, ok := l.(common.Mutable)
+		if !ok {
+			continue
+		}
+		deps := []dep{{"a", "b"}, {"b", "c"}, {"c", "d"}}
+		if err := prepareCase(l, deps).execute(); err != nil {
+			t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+		}
+		if err := m.RemoveDependency("b", "c"); err != nil {
+			t.Fatalf("%s failed during RemoveDependency: %s", l, err.Error())
+		}
+		if err := l.AddDependency("c", "a"); err != nil {
+			t.Errorf("Expected %s to allow c -> a after removing b -> c, but got: %v", l, err)
+		}
+		if err := m.RemovePhase("a"); err != nil {
+			t.Fatalf("%s failed during RemovePhase: %s", l, err.Error())
+		}
+		if err := l.AddPhase("a"); err != nil {
+			t.Fatalf("%s failed during AddPhase: %s", l, err.Error())
+		}
+		if err := l.AddDependency("b", "a"); err != nil {
+			t.Errorf("Expected %s to allow b -> a after removing a, but got: %v", l, err)
+		}
+		if err := l.AddDependency("d", "c"); !errors.As(err, new(*common.CycleError)) {
+			t.Errorf("Expected a *common.CycleError from %s for AddDependency(\"d\", \"c\") but got: %v", l, err)
+		}
+		checkValidOrder(t, l, []string{"a", "b", "c", "d"}, []dep{{"c", "d"}, {"b", "a"}})
+	}
+}
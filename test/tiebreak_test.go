@@ -0,0 +1,88 @@
+package test
+
+import (
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"testing"
+)
+
+// tieBreakCases all use determinismDeps, where e, d, a, f and g
+// are ready first, then b, and then c.
+var tieBreakCases = []struct {
+	tieBreak common.TieBreak
+	expected []string
+}{
+	{
+		tieBreak: common.InsertionOrder,
+		expected: []string{"e", "d", "a", "f", "g", "b", "c"},
+	},
+	{
+		tieBreak: common.Lexicographic,
+		expected: []string{"a", "d", "e", "f", "g", "b", "c"},
+	},
+	{
+		tieBreak: common.ByPriority(map[string]int{"f": 2, "g": 1, "d": -1}),
+		expected: []string{"f", "g", "e", "a", "d", "b", "c"},
+	},
+	{
+		// Reverse lexicographic order
+		tieBreak: func(a, b string) bool { return a > b },
+		expected: []string{"g", "f", "e", "d", "a", "b", "c"},
+	},
+}
+
+func TestMapsTieBreak(t *testing.T) {
+	testTieBreak(t, implementations.NewMaps)
+}
+
+func TestListsTieBreak(t *testing.T) {
+	testTieBreak(t, implementations.NewLists)
+}
+
+func TestKahnTieBreak(t *testing.T) {
+	testTieBreak(t, implementations.NewKahn)
+}
+
+func testTieBreak(t *testing.T, newLinearizer func(...implementations.Option) common.Linearizer) {
+	for _, tc := range tieBreakCases {
+		l := newLinearizer(implementations.WithTieBreak(tc.tieBreak))
+		runTestCase(t, l, testCase{
+			deps:     determinismDeps,
+			expected: tc.expected,
+		})
+	}
+	// The tie break only sorts the phases within each level, so a comes
+	// after c even though it sorts first, because it has to wait for b
+	l := newLinearizer(implementations.WithTieBreak(common.Lexicographic))
+	runTestCase(t, l, testCase{
+		deps:     []dep{{"c", ""}, {"b", "a"}},
+		expected: []string{"b", "c", "a"},
+	})
+}
+
+// TestTieBreakIgnored checks that the implementations which don't support
+// WithTieBreak return the same order with or without it
+func TestTieBreakIgnored(t *testing.T) {
+	constructors := map[string]func(...implementations.Option) common.Linearizer{
+		"Presort":     implementations.NewPresort,
+		"Graph":       implementations.NewGraph,
+		"Goraph":      implementations.NewGoraph,
+		"PearceKelly": implementations.NewPearceKelly,
+		"Unix": func(opts ...implementations.Option) common.Linearizer {
+			return implementations.NewUnix(unixOptions(opts...)...)
+		},
+	}
+	for name, newLinearizer := range constructors {
+		expected, err := linearizeCase(newLinearizer(), determinismDeps)
+		if err != nil {
+			t.Fatalf("%s failed for test case: %v\nGot error: %s", name, determinismDeps, err.Error())
+		}
+		for _, tc := range tieBreakCases {
+			l := newLinearizer(implementations.WithTieBreak(tc.tieBreak))
+			runTestCase(t, l, testCase{
+				deps:     determinismDeps,
+				expected: expected,
+			})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"github.com/albrow/dependency-linearization/tsort"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// tsortInputs are run through both the tsort command and the tsort package
+var tsortInputs = []string{
+	"",
+	"a a",
+	"a b b c c d",
+	"d c c b b a",
+	"a b\nb c\ta d \n",
+	"a b a b b c",
+	"x x y y z z a z",
+	"0 1 0 2 0 3 0 4 0 5 0 6 0 7 0 8 0 9 0 10",
+	// Loops, including loops which share items and more than one loop
+	"a b b a",
+	"a b b c c a b d d e",
+	"a b b c c a c d d b e f f e",
+	"1 2 2 3 3 4 4 1 4 2 3 1 5 1 5 5",
+	// Invalid input
+	"a b c",
+}
+
+func TestTsortMatchesCommand(t *testing.T) {
+	if !hasTsort {
+		t.Skip("tsort is not installed")
+	}
+	for _, input := range tsortInputs {
+		compareWithTsortCommand(t, input)
+	}
+}
+
+func FuzzTsort(f *testing.F) {
+	if !hasTsort {
+		f.Skip("tsort is not installed")
+	}
+	for _, input := range tsortInputs {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		// tsort treats a NUL byte as the end of a token,
+		// which the tsort package does not try to copy
+		if strings.ContainsRune(input, 0) {
+			t.Skip()
+		}
+		compareWithTsortCommand(t, input)
+	})
+}
+
+// compareWithTsortCommand checks that tsort.Run writes the same output
+// and errors as the tsort command for the given input.
+func compareWithTsortCommand(t *testing.T, input string) {
+	cmd := exec.Command("tsort")
+	cmd.Stdin = strings.NewReader(input)
+	expectedStdout, expectedStderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = expectedStdout, expectedStderr
+	cmdErr := cmd.Run()
+	if cmdErr != nil {
+		if _, ok := cmdErr.(*exec.ExitError); !ok {
+			t.Fatalf("Could not run tsort: %s", cmdErr.Error())
+		}
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := tsort.Run("-", strings.NewReader(input), stdout, stderr)
+	if (err == nil) != (cmdErr == nil) {
+		t.Errorf("tsort.Run returned %v but the tsort command returned %v for input %q", err, cmdErr, input)
+	}
+	if stdout.String() != expectedStdout.String() {
+		t.Errorf("tsort.Run wrote the wrong output for input %q.\n\tExpected: %q\n\tGot: %q",
+			input, expectedStdout.String(), stdout.String())
+	}
+	if stderr.String() != expectedStderr.String() {
+		t.Errorf("tsort.Run wrote the wrong errors for input %q.\n\tExpected: %q\n\tGot: %q",
+			input, expectedStderr.String(), stderr.String())
+	}
+}
+
+func TestTsortReadWrite(t *testing.T) {
+	pairs := []tsort.Pair{{First: "a", Second: "b"}, {First: "c", Second: "c"}}
+	buf := &bytes.Buffer{}
+	if err := tsort.Write(buf, pairs); err != nil {
+		t.Fatalf("Unexpected error from tsort.Write: %s", err.Error())
+	}
+	got, err := tsort.Read(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error from tsort.Read: %s", err.Error())
+	}
+	if len(got) != len(pairs) || got[0] != pairs[0] || got[1] != pairs[1] {
+		t.Errorf("tsort.Read did not read what tsort.Write wrote.\n\tExpected: %v\n\tGot: %v", pairs, got)
+	}
+
+	if _, err := tsort.Read(strings.NewReader("a b c")); !errors.Is(err, tsort.ErrOddTokens) {
+		t.Errorf("Expected ErrOddTokens from tsort.Read but got: %v", err)
+	}
+	for _, id := range []string{"", "a b", "a\tb", "a\nb"} {
+		err := tsort.Write(&bytes.Buffer{}, []tsort.Pair{{First: "a", Second: id}})
+		if !errors.Is(err, tsort.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken from tsort.Write for %q but got: %v", id, err)
+		}
+	}
+}
+
+// TestUnixWhitespace checks that ids with whitespace, which the tsort
+// command can't read, are rejected by default and work with InProcess.
+func TestUnixWhitespace(t *testing.T) {
+	if err := implementations.NewUnix().AddPhase("a b"); !errors.Is(err, tsort.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken from Unix for an id with a space but got: %v", err)
+	}
+	l := implementations.NewUnix(implementations.InProcess())
+	deps := []dep{{"second phase", "third\tphase"}, {"first phase", "second phase"}, {"", "first phase"}}
+	if err := prepareCase(l, deps).execute(); err != nil {
+		t.Fatalf("%s failed during preparation for test case: %v\nGot error: %s", l, deps, err.Error())
+	}
+	got, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during linearize: %s", l, err.Error())
+	}
+	if err := common.Validate([]string{"", "first phase", "second phase", "third\tphase"}, []common.Dependency{
+		{Before: "", After: "first phase"},
+		{Before: "first phase", After: "second phase"},
+		{Before: "second phase", After: "third\tphase"},
+	}, got); err != nil {
+		t.Errorf("%s returned an invalid order.\n\tGot: %q\n\tError: %s", l, got, err.Error())
+	}
+}
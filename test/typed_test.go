@@ -0,0 +1,261 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"github.com/albrow/dependency-linearization/typed"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// typedPairs holds each typed implementation along
+// with the implementation it was ported from
+var typedPairs = []struct {
+	typed   func() typed.Linearizer[string]
+	strings common.Factory
+}{
+	{typed.NewPresort[string], func() common.Linearizer { return implementations.NewPresort() }},
+	{typed.NewKahn[string], func() common.Linearizer { return implementations.NewKahn() }},
+}
+
+// TestTypedMatchesStrings checks that with string keys, the typed
+// implementations return exactly the same results as the originals
+func TestTypedMatchesStrings(t *testing.T) {
+	cases := [][]dep{
+		{{"a", "e"}, {"c", "d"}, {"a", "b"}, {"b", "c"}, {"b", "d"}, {"d", "e"}},
+		{{"d", "c"}, {"c", "b"}, {"b", "a"}},
+		{{"a", "b"}, {"c", ""}, {"d", "a"}, {"b", "e"}, {"e", "c"}},
+		{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}},
+		{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "b"}},
+		makeTreeDeps(10),
+		makeLinearDeps(10),
+	}
+	for _, pair := range typedPairs {
+		for _, deps := range cases {
+			l := pair.strings()
+			expected, expectedErr := linearizeCase(l, deps)
+			tl := pair.typed()
+			got, gotErr := linearizeTypedCase(tl, deps)
+			if expectedErr != nil {
+				var cycleErr *common.CycleError
+				if !errors.As(expectedErr, &cycleErr) {
+					t.Fatalf("Unexpected error from %s for %v: %s", l, deps, expectedErr.Error())
+				}
+				var typedErr *typed.CycleError[string]
+				if !errors.As(gotErr, &typedErr) {
+					t.Fatalf("Expected a *typed.CycleError from %s for %v but got: %v", tl, deps, gotErr)
+				}
+				if !reflect.DeepEqual(typedErr.Cycle, cycleErr.Cycle) || !reflect.DeepEqual(typedErr.Components, cycleErr.Components) {
+					t.Errorf("%s reported a different cycle than %s for %v.\n\tExpected: %v %v\n\tGot: %v %v",
+						tl, l, deps, cycleErr.Cycle, cycleErr.Components, typedErr.Cycle, typedErr.Components)
+				}
+				if gotErr.Error() != expectedErr.Error() {
+					t.Errorf("Wrong error from %s for %v.\n\tExpected: %s\n\tGot: %s", tl, deps, expectedErr.Error(), gotErr.Error())
+				}
+				continue
+			}
+			if gotErr != nil {
+				t.Fatalf("Unexpected error from %s for %v: %s", tl, deps, gotErr.Error())
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("%s returned a different order than %s for %v.\n\tExpected: %v\n\tGot: %v", tl, l, deps, expected, got)
+			}
+		}
+	}
+}
+
+func linearizeCase(l common.Linearizer, deps []dep) ([]string, error) {
+	if err := prepareCase(l, deps).execute(); err != nil {
+		return nil, err
+	}
+	return l.Linearize()
+}
+
+// linearizeTypedCase adds the phases and then the dependencies
+// in deps to l, the same way prepareCase does, and linearizes it
+func linearizeTypedCase(l typed.Linearizer[string], deps []dep) ([]string, error) {
+	added := map[string]struct{}{}
+	addPhase := func(id string) error {
+		if _, found := added[id]; found || id == "" {
+			return nil
+		}
+		added[id] = struct{}{}
+		return l.AddPhase(id)
+	}
+	for _, d := range deps {
+		if err := addPhase(d.first); err != nil {
+			return nil, err
+		}
+		if err := addPhase(d.second); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range deps {
+		if d.second == "" {
+			continue
+		}
+		if err := l.AddDependency(d.first, d.second); err != nil {
+			return nil, err
+		}
+	}
+	return l.Linearize()
+}
+
+// typedPhase stands in for the kind of struct people would
+// use as keys, which has no natural string id
+type typedPhase struct {
+	name string
+}
+
+func (p *typedPhase) String() string {
+	return p.name
+}
+
+func TestTypedPointers(t *testing.T) {
+	factories := []func() typed.ValueLinearizer[*typedPhase, int]{
+		typed.NewPresortWithValues[*typedPhase, int],
+		typed.NewKahnWithValues[*typedPhase, int],
+	}
+	for _, newLinearizer := range factories {
+		l := newLinearizer()
+		// Two phases with the same name are still different keys
+		a, b, c, otherA := &typedPhase{"a"}, &typedPhase{"b"}, &typedPhase{"c"}, &typedPhase{"a"}
+		phases := []*typedPhase{c, b, a, otherA}
+		for i, p := range phases {
+			if err := l.AddPhaseWithValue(p, i); err != nil {
+				t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+			}
+		}
+		for _, d := range [][2]*typedPhase{{a, b}, {b, c}, {otherA, c}} {
+			if err := l.MustRunBefore(d[0], d[1]); err != nil {
+				t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+			}
+		}
+		got, err := l.Linearize()
+		if err != nil {
+			t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+		}
+		positions := map[*typedPhase]int{}
+		for i, p := range got {
+			positions[p] = i
+		}
+		if len(got) != 4 || positions[a] > positions[b] || positions[b] > positions[c] || positions[otherA] > positions[c] {
+			t.Errorf("%s returned an invalid order: %v", l, got)
+		}
+		values, err := l.LinearizeValues()
+		if err != nil {
+			t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+		}
+		// Each value is the index of its phase in phases
+		for i, value := range values {
+			if i >= len(got) || phases[value] != got[i] {
+				t.Errorf("Values from %s were not in the same order as the phases.\n\tPhases: %v\n\tValues: %v", l, got, values)
+				break
+			}
+		}
+		if value, found := l.Value(otherA); !found || value != 3 {
+			t.Errorf("Expected Value to return 3 for %s but got: %d, %t", l, value, found)
+		}
+		if _, found := l.Value(&typedPhase{"a"}); found {
+			t.Errorf("Expected Value to return false for an unknown key for %s", l)
+		}
+
+		// The errors from common are wrapped with the key
+		unknown := &typedPhase{"unknown"}
+		if err := l.AddPhase(a); !errors.Is(err, common.ErrDuplicatePhase) {
+			t.Errorf("Expected common.ErrDuplicatePhase from %s but got: %v", l, err)
+		}
+		if err := l.AddDependency(a, unknown); !errors.Is(err, common.ErrUnknownPhase) {
+			t.Errorf("Expected common.ErrUnknownPhase from %s but got: %v", l, err)
+		} else if expected := "Could not find phase with id = unknown"; err.Error() != expected {
+			t.Errorf("Wrong error from %s.\n\tExpected: %s\n\tGot: %s", l, expected, err.Error())
+		}
+		if err := l.DependsOn(a, a); !errors.Is(err, common.ErrSelfDependency) {
+			t.Errorf("Expected common.ErrSelfDependency from %s but got: %v", l, err)
+		}
+
+		if err := l.AddDependency(c, a); err != nil {
+			t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+		}
+		_, err = l.Linearize()
+		var cycleErr *typed.CycleError[*typedPhase]
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("Expected a *typed.CycleError from %s but got: %v", l, err)
+		}
+		if len(cycleErr.Cycle) != 3 {
+			t.Errorf("Expected a cycle of 3 phases from %s but got: %v", l, cycleErr.Cycle)
+		} else if expected := fmt.Sprintf("Detected cycle: %s -> %s -> %s -> %s", cycleErr.Cycle[0], cycleErr.Cycle[1],
+			cycleErr.Cycle[2], cycleErr.Cycle[0]); err.Error() != expected {
+			t.Errorf("Wrong error from %s.\n\tExpected: %s\n\tGot: %s", l, expected, err.Error())
+		}
+
+		l.Reset()
+		if got, err := l.Linearize(); err != nil || len(got) != 0 {
+			t.Errorf("Expected no phases after Reset for %s but got: %v, %v", l, got, err)
+		}
+	}
+}
+
+func TestTypedLevels(t *testing.T) {
+	l := typed.NewKahn[int]()
+	for i := 0; i < 5; i++ {
+		if err := l.AddPhase(i); err != nil {
+			t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+		}
+	}
+	for _, d := range [][2]int{{0, 2}, {1, 2}, {2, 3}, {0, 4}} {
+		if err := l.AddDependency(d[0], d[1]); err != nil {
+			t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+		}
+	}
+	levels, err := l.(typed.Leveler[int]).LinearizeLevels()
+	if err != nil {
+		t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+	}
+	if expected := [][]int{{0, 1}, {2, 4}, {3}}; !reflect.DeepEqual(levels, expected) {
+		t.Errorf("Wrong levels from %s.\n\tExpected: %v\n\tGot: %v", l, expected, levels)
+	}
+}
+
+// checkTyped builds the graph from FuzzDifferential with each typed
+// implementation, using the index of each phase as its key
+func checkTyped(t *testing.T, phases []string, deps []common.Dependency, expectCycle bool) {
+	factories := []func() typed.Linearizer[int]{typed.NewPresort[int], typed.NewKahn[int]}
+	for _, newLinearizer := range factories {
+		l := newLinearizer()
+		for i := range phases {
+			if err := l.AddPhase(i); err != nil {
+				t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+			}
+		}
+		for _, d := range deps {
+			before, _ := strconv.Atoi(d.Before)
+			after, _ := strconv.Atoi(d.After)
+			if err := l.AddDependency(before, after); err != nil {
+				t.Fatalf("Unexpected error from %s: %s", l, err.Error())
+			}
+		}
+		got, err := l.Linearize()
+		if expectCycle {
+			var cycleErr *typed.CycleError[int]
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("Expected a *typed.CycleError from %s for phases %v and dependencies %v but got: %v", l, phases, deps, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s failed for phases %v and dependencies %v\nGot error: %s", l, phases, deps, err.Error())
+		}
+		order := make([]string, len(got))
+		for i, key := range got {
+			order[i] = strconv.Itoa(key)
+		}
+		if err := common.Validate(phases, deps, order); err != nil {
+			t.Fatalf("%s returned an invalid order for phases %v and dependencies %v\n\tGot: %v\n\tError: %s",
+				l, phases, deps, got, err.Error())
+		}
+	}
+}
@@ -2,6 +2,8 @@ package test
 
 import (
 	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"os/exec"
 	"strconv"
 	"testing"
 )
@@ -12,11 +14,28 @@ type testCase struct {
 }
 
 // dep defines a dependency by two
-// phase ids. If you want to represent
-// only one phase, leave dependsOn blank
+// phase ids: first must run before second.
+// If you want to represent only one phase,
+// leave second blank
 type dep struct {
-	depender  string
-	dependsOn string
+	first  string
+	second string
+}
+
+// hasTsort is true iff the tsort command is installed
+var hasTsort = func() bool {
+	_, err := exec.LookPath("tsort")
+	return err == nil
+}()
+
+// unixOptions returns opts for the Unix implementation. If the tsort command
+// is not installed, it adds the InProcess option so that the tests and
+// benchmarks can still run.
+func unixOptions(opts ...implementations.Option) []implementations.Option {
+	if !hasTsort {
+		return append(opts, implementations.InProcess())
+	}
+	return opts
 }
 
 // runTestCase runs l against a specific test case, which is defined
@@ -77,9 +96,9 @@ func prepareCase(l common.Linearizer, deps []dep) *preparer {
 		l:           l,
 	}
 	for _, dep := range deps {
-		p.nxAddPhase(dep.depender)
-		if dep.dependsOn != "" {
-			p.nxAddPhase(dep.dependsOn)
+		p.nxAddPhase(dep.first)
+		if dep.second != "" {
+			p.nxAddPhase(dep.second)
 			p.addDep(dep)
 		}
 	}
@@ -104,7 +123,7 @@ func (p *preparer) nxAddPhase(id string) {
 // will be executed.
 func (p *preparer) addDep(dep dep) {
 	p.depFuncs = append(p.depFuncs, func() error {
-		return p.l.AddDependency(dep.depender, dep.dependsOn)
+		return p.l.AddDependency(dep.first, dep.second)
 	})
 }
 
@@ -142,7 +161,7 @@ func (p *preparer) getFuncs() []func() error {
 }
 
 // makeTreeDeps returns a slice of deps arranged in a tree pattern.
-// i.e. some root phase depends on numBranches phases.
+// i.e. some root phase must run before numBranches phases.
 // Like this:
 //
 //       a
@@ -157,8 +176,8 @@ func makeTreeDeps(numBranches int) []dep {
 	// start with 1 and iterate to numBranches
 	for i := 1; i <= numBranches; i++ {
 		deps = append(deps, dep{
-			depender:  "0",
-			dependsOn: strconv.Itoa(i),
+			first:  "0",
+			second: strconv.Itoa(i),
 		})
 	}
 	return deps
@@ -177,8 +196,8 @@ func makeLinearDeps(numPhases int) []dep {
 	// start with 1 and iterate to numPhases
 	for i := 1; i < numPhases; i++ {
 		deps = append(deps, dep{
-			depender:  strconv.Itoa(i - 1),
-			dependsOn: strconv.Itoa(i),
+			first:  strconv.Itoa(i - 1),
+			second: strconv.Itoa(i),
 		})
 	}
 	return deps
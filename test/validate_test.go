@@ -0,0 +1,48 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	phases := []string{"a", "b", "c", "d"}
+	deps := []common.Dependency{{Before: "a", After: "b"}, {Before: "a", After: "c"}, {Before: "c", After: "d"}}
+	cases := []struct {
+		name     string
+		order    []string
+		expected error
+	}{
+		{"a valid order", []string{"a", "b", "c", "d"}, nil},
+		{"a different valid order", []string{"a", "c", "d", "b"}, nil},
+		{"a violated dependency", []string{"a", "b", "d", "c"}, common.ErrInvalidOrder},
+		{"a missing phase", []string{"a", "b", "c"}, common.ErrInvalidOrder},
+		{"a repeated phase", []string{"a", "b", "c", "d", "a"}, common.ErrInvalidOrder},
+		{"an unexpected phase", []string{"a", "b", "c", "d", "e"}, common.ErrInvalidOrder},
+	}
+	for _, c := range cases {
+		if err := common.Validate(phases, deps, c.order); !errors.Is(err, c.expected) {
+			t.Errorf("Expected %v from Validate for %s %v but got: %v", c.expected, c.name, c.order, err)
+		}
+	}
+
+	// The error should point out the first violated dependency
+	err := common.Validate(phases, deps, []string{"d", "c", "b", "a"})
+	expected := "Invalid order: a must run before b but came after it (position 3 > 2)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q from Validate but got: %v", expected, err)
+	}
+
+	// Dependencies on phases which don't exist can never be satisfied
+	err = common.Validate(phases, []common.Dependency{{Before: "a", After: "z"}}, phases)
+	if !errors.Is(err, common.ErrUnknownPhase) {
+		t.Errorf("Expected ErrUnknownPhase from Validate for a dependency on an unknown phase but got: %v", err)
+	}
+
+	// Neither can a phase which depends on itself
+	err = common.Validate(phases, []common.Dependency{{Before: "a", After: "b"}, {Before: "c", After: "c"}}, phases)
+	if !errors.Is(err, common.ErrSelfDependency) {
+		t.Errorf("Expected ErrSelfDependency from Validate for a phase which depends on itself but got: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"errors"
+	"github.com/albrow/dependency-linearization/common"
+	"github.com/albrow/dependency-linearization/implementations"
+	"reflect"
+	"testing"
+)
+
+func TestGraphValues(t *testing.T) {
+	testValues(t, implementations.NewGraph())
+}
+
+func TestPresortValues(t *testing.T) {
+	testValues(t, implementations.NewPresort())
+}
+
+func TestKahnValues(t *testing.T) {
+	testValues(t, implementations.NewKahn())
+	testValues(t, implementations.NewKahn(implementations.WithTieBreak(common.Lexicographic)))
+}
+
+func testValues(t *testing.T, l common.Linearizer) {
+	defer l.Reset()
+	v, ok := l.(common.Valuer)
+	if !ok {
+		t.Fatalf("%s does not implement common.Valuer", l)
+	}
+
+	// Every phase but d has a value, and they are added out
+	// of order so that some of them have to be moved
+	for _, id := range []string{"c", "b", "a"} {
+		if err := v.AddPhaseWithValue(id, "value of "+id); err != nil {
+			t.Fatalf("%s failed during AddPhaseWithValue: %s", l, err.Error())
+		}
+	}
+	if err := l.AddPhase("d"); err != nil {
+		t.Fatalf("%s failed during AddPhase: %s", l, err.Error())
+	}
+	for _, d := range []dep{{"a", "b"}, {"b", "c"}, {"d", "c"}} {
+		if err := l.AddDependency(d.first, d.second); err != nil {
+			t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+		}
+	}
+	if err := v.AddPhaseWithValue("a", "another value"); !errors.Is(err, common.ErrDuplicatePhase) {
+		t.Errorf("Expected common.ErrDuplicatePhase from %s but got: %v", l, err)
+	}
+
+	ids, err := l.Linearize()
+	if err != nil {
+		t.Fatalf("%s failed during Linearize: %s", l, err.Error())
+	}
+	values, err := v.LinearizeValues()
+	if err != nil {
+		t.Fatalf("%s failed during LinearizeValues: %s", l, err.Error())
+	}
+	expected := []interface{}{}
+	for _, id := range ids {
+		if id == "d" {
+			expected = append(expected, nil)
+		} else {
+			expected = append(expected, "value of "+id)
+		}
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("LinearizeValues returned the wrong values for %s.\n\tOrder: %v\n\tExpected: %v\n\tGot: %v", l, ids, expected, values)
+	}
+
+	if value, found := v.Value("b"); !found || value != "value of b" {
+		t.Errorf("Expected Value(\"b\") to return \"value of b\" for %s but got: %v, %t", l, value, found)
+	}
+	if value, found := v.Value("d"); !found || value != nil {
+		t.Errorf("Expected Value(\"d\") to return nil for %s but got: %v, %t", l, value, found)
+	}
+	if _, found := v.Value("e"); found {
+		t.Errorf("Expected Value(\"e\") to return false for %s", l)
+	}
+
+	// Removing a phase should keep the values of the others
+	if m, ok := l.(common.Mutable); ok {
+		if err := m.RemovePhase("d"); err != nil {
+			t.Fatalf("%s failed during RemovePhase: %s", l, err.Error())
+		}
+		values, err := v.LinearizeValues()
+		if err != nil {
+			t.Fatalf("%s failed during LinearizeValues: %s", l, err.Error())
+		}
+		expected := []interface{}{"value of a", "value of b", "value of c"}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("LinearizeValues returned the wrong values for %s after RemovePhase.\n\tExpected: %v\n\tGot: %v", l, expected, values)
+		}
+	}
+
+	// LinearizeValues reports cycles the same way Linearize does
+	if err := l.AddDependency("c", "a"); err != nil {
+		t.Fatalf("%s failed during AddDependency: %s", l, err.Error())
+	}
+	_, expectedErr := l.Linearize()
+	_, err = v.LinearizeValues()
+	var cycleErr *common.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a *common.CycleError from %s but got: %v", l, err)
+	}
+	if expectedErr == nil || err.Error() != expectedErr.Error() {
+		t.Errorf("LinearizeValues returned a different error than Linearize for %s.\n\tExpected: %v\n\tGot: %v", l, expectedErr, err)
+	}
+}
@@ -0,0 +1,286 @@
+// Package tsort is a pure go version of the tsort command from GNU coreutils.
+// It reads pairs of items, and sorts the items so that the first item of every
+// pair comes before the second. Given the same input, it returns the items in
+// the same order as GNU tsort, and reports the same loops when there isn't a
+// valid order.
+package tsort
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrOddTokens is returned by Read when the input can't be split into pairs
+	ErrOddTokens = errors.New("Input contains an odd number of tokens")
+	// ErrLoop is returned by Run when the input contains a loop
+	ErrLoop = errors.New("Input contains a loop")
+	// ErrInvalidToken is returned by Write for an item which can't be
+	// written as a single token, i.e. one which is empty or contains
+	// whitespace.
+	ErrInvalidToken = errors.New("Invalid token")
+)
+
+// delimiters are the characters which separate tokens. They are the same
+// ones GNU tsort uses, which is not every character that counts as
+// whitespace in go.
+const delimiters = " \t\n"
+
+// Pair means that First must come before Second. If First and Second are the
+// same, the pair just means that the item exists.
+type Pair struct {
+	First  string
+	Second string
+}
+
+// Read reads pairs in the format tsort expects, i.e. tokens separated by
+// spaces, tabs or newlines, where each two tokens make up a pair.
+func Read(r io.Reader) ([]Pair, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens := strings.FieldsFunc(string(input), func(c rune) bool {
+		return strings.ContainsRune(delimiters, c)
+	})
+	if len(tokens)%2 != 0 {
+		return nil, ErrOddTokens
+	}
+	pairs := make([]Pair, 0, len(tokens)/2)
+	for i := 0; i < len(tokens); i += 2 {
+		pairs = append(pairs, Pair{First: tokens[i], Second: tokens[i+1]})
+	}
+	return pairs, nil
+}
+
+// Write writes pairs in the format tsort expects, one pair per line.
+func Write(w io.Writer, pairs []Pair) error {
+	for _, p := range pairs {
+		if err := CheckToken(p.First); err != nil {
+			return err
+		}
+		if err := CheckToken(p.Second); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", p.First, p.Second); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckToken returns an error wrapping ErrInvalidToken if item can't
+// be written as a single token.
+func CheckToken(item string) error {
+	if item == "" || strings.ContainsAny(item, delimiters) {
+		return fmt.Errorf("%w: %q", ErrInvalidToken, item)
+	}
+	return nil
+}
+
+// Run works like the tsort command. It reads pairs from stdin, writes the
+// sorted items to stdout, one per line, and writes any errors to stderr.
+// name is the name of the input which is used in the error messages, which
+// is "-" for tsort's standard input. If stdin contains a loop, Run still
+// writes every item, but it returns ErrLoop.
+func Run(name string, stdin io.Reader, stdout, stderr io.Writer) error {
+	pairs, err := Read(stdin)
+	if err != nil {
+		if errors.Is(err, ErrOddTokens) {
+			fmt.Fprintf(stderr, "tsort: %s: input contains an odd number of tokens\n", name)
+		}
+		return err
+	}
+	order, loops := Sort(pairs)
+	for _, item := range order {
+		if _, err := fmt.Fprintln(stdout, item); err != nil {
+			return err
+		}
+	}
+	for _, loop := range loops {
+		fmt.Fprintf(stderr, "tsort: %s: input contains a loop:\n", name)
+		for _, item := range loop {
+			fmt.Fprintf(stderr, "tsort: %s\n", item)
+		}
+	}
+	if len(loops) > 0 {
+		return ErrLoop
+	}
+	return nil
+}
+
+// item is a single item to be sorted, along with the relations it is a part of
+type item struct {
+	str     string
+	printed bool
+	// count is the number of items which must come before this one
+	// which have not been printed yet
+	count int
+	// qlink is the next item in the queue of items which are ready to be
+	// printed. It is also used to keep track of the path while looking
+	// for a loop.
+	qlink *item
+	// top is the most recently added of the items which
+	// must come after this one
+	top *successor
+}
+
+// successor is a linked list of items which must come after another item
+type successor struct {
+	suc  *item
+	next *successor
+}
+
+// sorter holds the state of a single call to Sort
+type sorter struct {
+	// items holds every item sorted by str, which is the order GNU tsort
+	// visits them in when it walks its search tree.
+	items []*item
+	// head and zeros are the front and back of the queue of items
+	// which are ready to be printed
+	head  *item
+	zeros *item
+	// loop is the last item on the path detectLoop is following
+	loop *item
+	// current is the loop which detectLoop is reporting
+	current []string
+}
+
+// Sort returns the items in pairs in the same order GNU tsort would write
+// them. If there are loops, Sort still returns every item, along with each
+// loop which tsort would report. To get an order, tsort ignores one of the
+// pairs in each loop, so the order does not satisfy every pair.
+func Sort(pairs []Pair) ([]string, [][]string) {
+	s := &sorter{}
+	s.addPairs(pairs)
+	order := make([]string, 0, len(s.items))
+	loops := [][]string{}
+	remaining := len(s.items)
+	for remaining > 0 {
+		// Queue up every item which has no remaining items to come before it
+		s.walk(s.scanZeros)
+		for s.head != nil {
+			order = append(order, s.head.str)
+			s.head.printed = true
+			remaining--
+			for p := s.head.top; p != nil; p = p.next {
+				p.suc.count--
+				if p.suc.count == 0 {
+					s.zeros.qlink = p.suc
+					s.zeros = p.suc
+				}
+			}
+			s.head = s.head.qlink
+		}
+		if remaining > 0 {
+			// The remaining items contain a loop. Find one and remove
+			// one of its pairs to break it.
+			s.current = []string{}
+			for {
+				s.walk(s.detectLoop)
+				if s.loop == nil {
+					break
+				}
+			}
+			loops = append(loops, s.current)
+		}
+	}
+	return order, loops
+}
+
+// addPairs records every pair. Later pairs go in front of earlier ones
+// in each successor list, just like they do in GNU tsort.
+func (s *sorter) addPairs(pairs []Pair) {
+	items := map[string]*item{}
+	find := func(str string) *item {
+		if it, found := items[str]; found {
+			return it
+		}
+		it := &item{str: str}
+		items[str] = it
+		s.items = append(s.items, it)
+		return it
+	}
+	for _, p := range pairs {
+		j, k := find(p.First), find(p.Second)
+		if j != k {
+			k.count++
+			j.top = &successor{suc: k, next: j.top}
+		}
+	}
+	sort.Slice(s.items, func(i, j int) bool {
+		return s.items[i].str < s.items[j].str
+	})
+}
+
+// walk calls action for each item in order, until action returns true
+func (s *sorter) walk(action func(k *item) bool) {
+	for _, k := range s.items {
+		if action(k) {
+			return
+		}
+	}
+}
+
+// scanZeros adds k to the queue if it is ready to be printed
+func (s *sorter) scanZeros(k *item) bool {
+	if k.count == 0 && !k.printed {
+		if s.head == nil {
+			s.head = k
+		} else {
+			s.zeros.qlink = k
+		}
+		s.zeros = k
+	}
+	return false
+}
+
+// detectLoop follows the items which must come before each other backwards,
+// starting with the first item which is not ready. Once the path reaches an
+// item which is already on it, it records the loop, removes the pair which
+// closes it and returns true.
+func (s *sorter) detectLoop(k *item) bool {
+	if k.count == 0 {
+		return false
+	}
+	if s.loop == nil {
+		// Start following the path at k
+		s.loop = k
+		return false
+	}
+	for p := &k.top; *p != nil; p = &(*p).next {
+		if (*p).suc != s.loop {
+			continue
+		}
+		if k.qlink == nil {
+			// k comes before the last item on the path, so it goes next
+			k.qlink = s.loop
+			s.loop = k
+			return false
+		}
+		// We found a loop. Retrace the path until we get back to k.
+		for s.loop != nil {
+			next := s.loop.qlink
+			s.current = append(s.current, s.loop.str)
+			if s.loop == k {
+				// Remove the pair
+				(*p).suc.count--
+				*p = (*p).next
+				break
+			}
+			s.loop.qlink = nil
+			s.loop = next
+		}
+		// Tidy up in case we have to find another loop
+		for s.loop != nil {
+			next := s.loop.qlink
+			s.loop.qlink = nil
+			s.loop = next
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package typed
+
+import (
+	"github.com/albrow/dependency-linearization/internal/core"
+)
+
+// kahnType is core.Kahn, the same algorithm as implementations.NewKahn
+type kahnType[K comparable, V any] struct {
+	*core.Kahn[K, V]
+}
+
+// NewKahn returns a new, independent implementation of Kahn's algorithm,
+// which works the same way as implementations.NewKahn. It also implements
+// Leveler[K].
+func NewKahn[K comparable]() Linearizer[K] {
+	return newKahn[K, struct{}]()
+}
+
+// NewKahnWithValues is the same as NewKahn, but
+// holds a value of type V for each phase.
+func NewKahnWithValues[K comparable, V any]() ValueLinearizer[K, V] {
+	return newKahn[K, V]()
+}
+
+func newKahn[K comparable, V any]() *kahnType[K, V] {
+	return &kahnType[K, V]{
+		Kahn: core.NewKahn[K, V](coreOptions[K]()),
+	}
+}
+
+func (k *kahnType[K, V]) MustRunBefore(first, second K) error {
+	return k.AddDependency(first, second)
+}
+
+func (k *kahnType[K, V]) DependsOn(dependent, prerequisite K) error {
+	return k.AddDependency(prerequisite, dependent)
+}
+
+func (k *kahnType[K, V]) String() string {
+	return "Typed Kahn implementation"
+}
@@ -0,0 +1,79 @@
+// Package typed holds linearizers whose phases are identified by keys of any
+// comparable type, e.g. pointers to your own phase structs, instead of by
+// strings. They work the same way as their counterparts in implementations
+// and return the same errors from common, wrapped with the keys involved.
+package typed
+
+import (
+	"fmt"
+	"github.com/albrow/dependency-linearization/internal/core"
+	"strings"
+)
+
+// Linearizer is the same as common.Linearizer,
+// but phases are identified by keys of type K.
+type Linearizer[K comparable] interface {
+	AddPhase(K) error
+	// Linearize returns the keys of all the phases, ordered so that
+	// every phase comes after the phases it depends on.
+	Linearize() ([]K, error)
+	// AddDependency declares that b depends on a, so a will come
+	// before b in the linearized order.
+	AddDependency(a, b K) error
+	// MustRunBefore is the same as AddDependency(first, second).
+	MustRunBefore(first, second K) error
+	// DependsOn is the same as AddDependency(prerequisite, dependent).
+	DependsOn(dependent, prerequisite K) error
+	// Reset clears all previous phases
+	Reset()
+}
+
+// ValueLinearizer is a Linearizer which holds a value of type V for each
+// phase, so the phases can be linearized straight into the values which
+// need to run.
+type ValueLinearizer[K comparable, V any] interface {
+	Linearizer[K]
+	// AddPhaseWithValue adds a phase with the given key which holds value.
+	// AddPhase adds a phase which holds the zero value of V.
+	AddPhaseWithValue(key K, value V) error
+	// Value returns the value of the phase with the given key, or false
+	// if there is no such phase.
+	Value(key K) (V, bool)
+	// LinearizeValues is the same as Linearize, but returns
+	// the value of each phase instead of its key.
+	LinearizeValues() ([]V, error)
+}
+
+// Leveler is the same as common.Leveler,
+// but phases are identified by keys of type K.
+type Leveler[K comparable] interface {
+	LinearizeLevels() ([][]K, error)
+}
+
+// CycleError is the same as common.CycleError,
+// but phases are identified by keys of type K.
+type CycleError[K comparable] struct {
+	Cycle      []K
+	Components [][]K
+}
+
+func (e *CycleError[K]) Error() string {
+	if len(e.Cycle) == 0 {
+		return "Detected cycle!"
+	}
+	ids := make([]string, len(e.Cycle))
+	for i, key := range e.Cycle {
+		ids[i] = fmt.Sprint(key)
+	}
+	return fmt.Sprintf("Detected cycle: %s -> %s", strings.Join(ids, " -> "), ids[0])
+}
+
+// coreOptions returns the options of the linearizers in internal/core,
+// which report cycles as a *CycleError[K]
+func coreOptions[K comparable]() core.Options[K] {
+	return core.Options[K]{
+		CycleError: func(cycle []K, components [][]K) error {
+			return &CycleError[K]{Cycle: cycle, Components: components}
+		},
+	}
+}
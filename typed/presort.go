@@ -0,0 +1,41 @@
+package typed
+
+import (
+	"github.com/albrow/dependency-linearization/internal/core"
+)
+
+// presortType is core.Presort, the same algorithm as implementations.NewPresort
+type presortType[K comparable, V any] struct {
+	*core.Presort[K, V]
+}
+
+// NewPresort returns a new, independent Presort implementation, which works
+// the same way as implementations.NewPresort. Phases are kept in the order
+// they were added unless a dependency requires moving them.
+func NewPresort[K comparable]() Linearizer[K] {
+	return newPresort[K, struct{}]()
+}
+
+// NewPresortWithValues is the same as NewPresort,
+// but holds a value of type V for each phase.
+func NewPresortWithValues[K comparable, V any]() ValueLinearizer[K, V] {
+	return newPresort[K, V]()
+}
+
+func newPresort[K comparable, V any]() *presortType[K, V] {
+	return &presortType[K, V]{
+		Presort: core.NewPresort[K, V](coreOptions[K]()),
+	}
+}
+
+func (t *presortType[K, V]) MustRunBefore(first, second K) error {
+	return t.AddDependency(first, second)
+}
+
+func (t *presortType[K, V]) DependsOn(dependent, prerequisite K) error {
+	return t.AddDependency(prerequisite, dependent)
+}
+
+func (t *presortType[K, V]) String() string {
+	return "Typed Presort implementation"
+}